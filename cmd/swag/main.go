@@ -13,6 +13,7 @@ import (
 	"github.com/swaggo/swag"
 	"github.com/swaggo/swag/format"
 	"github.com/swaggo/swag/gen"
+	"github.com/swaggo/swag/lsp"
 )
 
 const (
@@ -36,6 +37,12 @@ const (
 	quietFlag             = "quiet"
 	tagsFlag              = "tags"
 	parseExtensionFlag    = "parseExtension"
+	embedSpecFlag         = "embedSpec"
+	packageNameFlag       = "packageName"
+	docTemplateFlag       = "docTemplate"
+	transformersFlag      = "transformers"
+	stdoutFlag            = "stdout"
+	paramSeparatorFlag    = "paramSeparator"
 )
 
 var initFlags = []cli.Flag{
@@ -76,7 +83,7 @@ var initFlags = []cli.Flag{
 		Name:    outputTypesFlag,
 		Aliases: []string{"ot"},
 		Value:   "go,json,yaml",
-		Usage:   "Output types of generated files (docs.go, swagger.json, swagger.yaml) like go,json,yaml",
+		Usage:   "Output types of generated files (docs.go, swagger.json, swagger.yaml, swagger_contract_test.go) like go,json,yaml,contract",
 	},
 	&cli.BoolFlag{
 		Name:  parseVendorFlag,
@@ -136,6 +143,34 @@ var initFlags = []cli.Flag{
 		Value: "",
 		Usage: "Parse only those operations that match given extension",
 	},
+	&cli.BoolFlag{
+		Name:  embedSpecFlag,
+		Usage: "Generate docs.go embedding swagger.json with go:embed instead of inlining the spec, disabled by default",
+	},
+	&cli.StringFlag{
+		Name:  packageNameFlag,
+		Value: "",
+		Usage: "Package name of the generated docs.go, the base name of the output directory by default",
+	},
+	&cli.StringFlag{
+		Name:  docTemplateFlag,
+		Value: "",
+		Usage: "Go template file used to generate docs.go instead of the built-in template",
+	},
+	&cli.StringFlag{
+		Name:  transformersFlag,
+		Value: "",
+		Usage: "Comma-separated list of transformers applied in order to the spec before it is written, like pruneDefinitions,sortTags,stripExtensions:^x-internal",
+	},
+	&cli.StringFlag{
+		Name:  stdoutFlag,
+		Usage: "Print the given output type (go, json, yaml) to standard output instead of writing files",
+	},
+	&cli.StringFlag{
+		Name:  paramSeparatorFlag,
+		Value: ".",
+		Usage: "Separator joining the names of nested struct fields expanded into params, like filter.name",
+	},
 	&cli.StringFlag{
 		Name:    tagsFlag,
 		Aliases: []string{"t"},
@@ -157,12 +192,31 @@ func initAction(ctx *cli.Context) error {
 	if len(outputTypes) == 0 {
 		return fmt.Errorf("no output types specified")
 	}
+	var transformers []gen.Transformer
+
+	if ctx.String(transformersFlag) != "" {
+		for _, def := range strings.Split(ctx.String(transformersFlag), ",") {
+			transformer, err := gen.NewBuiltinTransformer(strings.TrimSpace(def))
+			if err != nil {
+				return err
+			}
+
+			transformers = append(transformers, transformer)
+		}
+	}
+
+	stdout := ctx.String(stdoutFlag)
+
 	logger := log.New(os.Stdout, "", log.LstdFlags)
+	if stdout != "" {
+		// keep standard output clean for the spec
+		logger = log.New(os.Stderr, "", log.LstdFlags)
+	}
 	if ctx.Bool(quietFlag) {
 		logger = log.New(io.Discard, "", log.LstdFlags)
 	}
 
-	return gen.New().Build(&gen.Config{
+	config := &gen.Config{
 		SearchDir:           ctx.String(searchDirFlag),
 		Excludes:            ctx.String(excludeFlag),
 		ParseExtension:      ctx.String(parseExtensionFlag),
@@ -182,8 +236,26 @@ func initAction(ctx *cli.Context) error {
 		OverridesFile:       ctx.String(overridesFileFlag),
 		ParseGoList:         ctx.Bool(parseGoListFlag),
 		Tags:                ctx.String(tagsFlag),
+		EmbedSpec:           ctx.Bool(embedSpecFlag),
+		PackageName:         ctx.String(packageNameFlag),
+		DocTemplateFile:     ctx.String(docTemplateFlag),
+		Transformers:        transformers,
+		ParamSeparator:      ctx.String(paramSeparatorFlag),
 		Debugger:            logger,
-	})
+	}
+
+	if stdout != "" {
+		g := gen.New()
+
+		swagger, err := g.Parse(config)
+		if err != nil {
+			return err
+		}
+
+		return g.WriteTo(os.Stdout, stdout, config, swagger)
+	}
+
+	return gen.New().Build(config)
 }
 
 func main() {
@@ -232,6 +304,41 @@ func main() {
 				},
 			},
 		},
+		{
+			Name:  "lsp",
+			Usage: "Run a language server for swag annotations over stdio",
+			Action: func(c *cli.Context) error {
+				return lsp.New(&lsp.Config{
+					SearchDir:       c.String(searchDirFlag),
+					Excludes:        c.String(excludeFlag),
+					ParseDependency: c.Bool(parseDependencyFlag),
+					MainAPIFile:     c.String(generalInfoFlag),
+					Debugger:        log.New(os.Stderr, "", log.LstdFlags),
+				}).Serve(os.Stdin, os.Stdout)
+			},
+			Flags: []cli.Flag{
+				&cli.StringFlag{
+					Name:    searchDirFlag,
+					Aliases: []string{"d"},
+					Usage:   "Directories to load Go types from, comma separated, the workspace folders of the client by default",
+				},
+				&cli.StringFlag{
+					Name:  excludeFlag,
+					Usage: "Exclude directories and files when searching, comma separated",
+				},
+				&cli.BoolFlag{
+					Name:    parseDependencyFlag,
+					Aliases: []string{"pd"},
+					Usage:   "Load Go types inside dependency folder, disabled by default",
+				},
+				&cli.StringFlag{
+					Name:    generalInfoFlag,
+					Aliases: []string{"g"},
+					Value:   "main.go",
+					Usage:   "Go file path in which 'swagger general API Info' is written, its reusable definitions are loaded",
+				},
+			},
+		},
 	}
 
 	if err := app.Run(os.Args); err != nil {
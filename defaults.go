@@ -0,0 +1,374 @@
+package swag
+
+import (
+	"fmt"
+	"go/ast"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/go-openapi/spec"
+)
+
+// operationScope restricts a default of the general API info to some operations,
+// e.g. `tag=admin path=/admin method=post,put`. An empty scope matches every operation.
+type operationScope struct {
+	tags       []string
+	pathPrefix string
+	methods    []string
+}
+
+// parseOperationScope parses the leading key=value fields of value, it returns the scope and the rest of value.
+func parseOperationScope(value string) (operationScope, string, error) {
+	var scope operationScope
+
+	for {
+		fields := FieldsByAnySpace(value, 2)
+		if len(fields) == 0 || !strings.Contains(fields[0], "=") {
+			return scope, value, nil
+		}
+
+		key, list := splitKeyValue(fields[0])
+
+		switch strings.ToLower(key) {
+		case "tag", "tags":
+			for _, tag := range strings.Split(list, ",") {
+				scope.tags = append(scope.tags, strings.TrimSpace(tag))
+			}
+		case "path":
+			scope.pathPrefix = list
+		case "method", "methods":
+			for _, method := range strings.Split(list, ",") {
+				scope.methods = append(scope.methods, strings.ToUpper(strings.TrimSpace(method)))
+			}
+		default:
+			return scope, "", fmt.Errorf("unknown scope %s, expected tag, path or method", key)
+		}
+
+		value = ""
+		if len(fields) > 1 {
+			value = fields[1]
+		}
+	}
+}
+
+func splitKeyValue(field string) (string, string) {
+	index := strings.Index(field, "=")
+
+	return field[:index], field[index+1:]
+}
+
+// match reports whether operation is in the scope, one of its routes must match the path prefix and methods.
+func (scope operationScope) match(operation *Operation) bool {
+	if len(scope.tags) > 0 {
+		tagged := false
+
+		for _, tag := range operation.Tags {
+			if findInSlice(scope.tags, tag) {
+				tagged = true
+
+				break
+			}
+		}
+
+		if !tagged {
+			return false
+		}
+	}
+
+	if scope.pathPrefix == "" && len(scope.methods) == 0 {
+		return true
+	}
+
+	for _, route := range operation.RouterProperties {
+		if strings.HasPrefix(route.Path, scope.pathPrefix) &&
+			(len(scope.methods) == 0 || findInSlice(scope.methods, route.HTTPMethod)) {
+			return true
+		}
+	}
+
+	return false
+}
+
+// defaultResponse a response or response header of the general API info, e.g.
+// `@default.response 500 {object} web.APIError "internal error"` or `@default.header all {string} X-Request-Id "id"`.
+type defaultResponse struct {
+	scope operationScope
+
+	// responses the responses of a @default.response
+	responses *spec.Responses
+
+	// headerName, header and headerCodes the header of a @default.header and the status codes it is added to
+	headerName  string
+	header      spec.Header
+	headerCodes []string
+}
+
+func parseDefaultResponse(operation *Operation, isHeader bool, value string, file *ast.File) (defaultResponse, error) {
+	scope, commentLine, err := parseOperationScope(value)
+	if err != nil {
+		return defaultResponse{}, err
+	}
+
+	if !isHeader {
+		err = operation.ParseResponseComment(commentLine, file)
+		if err != nil {
+			return defaultResponse{}, err
+		}
+
+		return defaultResponse{scope: scope, responses: operation.Responses}, nil
+	}
+
+	matches := responsePattern.FindStringSubmatch(commentLine)
+	if len(matches) != 5 {
+		return defaultResponse{}, fmt.Errorf("can not parse response comment \"%s\"", commentLine)
+	}
+
+	codes := strings.Split(matches[1], ",")
+	for _, code := range codes {
+		if strings.EqualFold(code, "all") || strings.EqualFold(code, defaultTag) {
+			continue
+		}
+
+		_, err = strconv.Atoi(code)
+		if err != nil {
+			return defaultResponse{}, fmt.Errorf("can not parse response comment \"%s\"", commentLine)
+		}
+	}
+
+	return defaultResponse{
+		scope:       scope,
+		headerName:  strings.TrimSpace(matches[3]),
+		header:      newHeaderSpec(strings.Trim(matches[2], "{}"), strings.Trim(matches[4], "\"")),
+		headerCodes: codes,
+	}, nil
+}
+
+// applyDefaultResponses merges the default responses, then the default response headers, in the scope of operation.
+// Responses and headers which the operation declares itself are kept.
+func (parser *Parser) applyDefaultResponses(operation *Operation) {
+	for _, def := range parser.defaultResponses {
+		if def.responses != nil && def.scope.match(operation) {
+			def.applyResponses(operation)
+		}
+	}
+
+	for _, def := range parser.defaultResponses {
+		if def.responses == nil && def.scope.match(operation) {
+			def.applyHeader(operation)
+		}
+	}
+}
+
+func (def *defaultResponse) applyResponses(operation *Operation) {
+	if def.responses.Default != nil && operation.Responses.Default == nil {
+		operation.Responses.Default = copyResponse(*def.responses.Default)
+	}
+
+	for code, response := range def.responses.StatusCodeResponses {
+		if _, ok := operation.Responses.StatusCodeResponses[code]; !ok {
+			operation.AddResponse(code, copyResponse(response))
+		}
+	}
+}
+
+func (def *defaultResponse) applyHeader(operation *Operation) {
+	for _, codeStr := range def.headerCodes {
+		switch {
+		case strings.EqualFold(codeStr, "all"):
+			def.setHeader(operation.Responses.Default)
+
+			for code, response := range operation.Responses.StatusCodeResponses {
+				def.setHeader(&response)
+				operation.Responses.StatusCodeResponses[code] = response
+			}
+		case strings.EqualFold(codeStr, defaultTag):
+			def.setHeader(operation.Responses.Default)
+		default:
+			code, _ := strconv.Atoi(codeStr)
+
+			response, ok := operation.Responses.StatusCodeResponses[code]
+			if ok {
+				def.setHeader(&response)
+				operation.Responses.StatusCodeResponses[code] = response
+			}
+		}
+	}
+}
+
+// setHeader adds the header to response unless it already has one with the same name.
+// A reference to a response of the general API info is left as is.
+func (def *defaultResponse) setHeader(response *spec.Response) {
+	if response == nil || response.Ref.String() != "" {
+		return
+	}
+
+	if response.Headers == nil {
+		response.Headers = make(map[string]spec.Header)
+	}
+
+	if _, ok := response.Headers[def.headerName]; !ok {
+		response.Headers[def.headerName] = def.header
+	}
+}
+
+// copyResponse copies response with its own headers, so the operations it is merged into don't share them.
+func copyResponse(response spec.Response) *spec.Response {
+	headers := make(map[string]spec.Header, len(response.Headers))
+	for name, header := range response.Headers {
+		headers[name] = header
+	}
+
+	response.Headers = headers
+
+	return &response
+}
+
+// packageDefaults the operation defaults of a package doc comment, usually in doc.go. @Tags, @Accept, @Produce
+// and @Security apply to every operation of the package which doesn't declare them itself, @Router prefixes its paths.
+//
+//	// Package pets handles the pets of the store.
+//	//
+//	// @Tags pets
+//	// @Produce json
+//	// @Router /pets
+//	package pets
+type packageDefaults struct {
+	operation    *Operation
+	routerPrefix string
+
+	// tagComments the @Tags comments, to filter the operations with the tags of the package
+	tagComments []*ast.Comment
+}
+
+// getPackageDefaults returns the operation defaults of the package of fileInfo, nil if it has none.
+func (parser *Parser) getPackageDefaults(fileInfo *AstFileInfo) (*packageDefaults, error) {
+	if defaults, ok := parser.packageDefaults[fileInfo.PackagePath]; ok {
+		return defaults, nil
+	}
+
+	var infos []*AstFileInfo
+
+	for _, info := range parser.packages.files {
+		if info.PackagePath == fileInfo.PackagePath && info.File.Doc != nil {
+			infos = append(infos, info)
+		}
+	}
+
+	sort.Slice(infos, func(i, j int) bool {
+		return infos[i].Path < infos[j].Path
+	})
+
+	var defaults *packageDefaults
+
+	for _, info := range infos {
+		// the general API info of the main package
+		if hasAttribute(info.File.Doc, titleAttr) {
+			continue
+		}
+
+		for _, comment := range info.File.Doc.List {
+			commentLine := strings.TrimSpace(strings.TrimLeft(comment.Text, "/"))
+
+			fields := FieldsByAnySpace(commentLine, 2)
+			if len(fields) == 0 {
+				continue
+			}
+
+			attribute := strings.ToLower(fields[0])
+
+			switch attribute {
+			case tagsAttr, acceptAttr, produceAttr, securityAttr, routerAttr:
+			default:
+				continue
+			}
+
+			if defaults == nil {
+				defaults = &packageDefaults{operation: NewOperation(parser)}
+			}
+
+			if attribute == routerAttr {
+				if len(fields) == 1 || !strings.HasPrefix(fields[1], "/") {
+					return nil, fmt.Errorf("ParseComment error in file %s :can not parse router prefix \"%s\"", info.Path, commentLine)
+				}
+
+				defaults.routerPrefix = strings.TrimSpace(fields[1])
+
+				continue
+			}
+
+			if attribute == tagsAttr {
+				defaults.tagComments = append(defaults.tagComments, comment)
+			}
+
+			err := defaults.operation.ParseComment(comment.Text, info.File)
+			if err != nil {
+				return nil, fmt.Errorf("ParseComment error in file %s :%+v", info.Path, err)
+			}
+		}
+	}
+
+	if parser.packageDefaults == nil {
+		parser.packageDefaults = make(map[string]*packageDefaults)
+	}
+
+	parser.packageDefaults[fileInfo.PackagePath] = defaults
+
+	return defaults, nil
+}
+
+func hasAttribute(doc *ast.CommentGroup, attribute string) bool {
+	for _, comment := range doc.List {
+		fields := FieldsByAnySpace(strings.TrimSpace(strings.TrimLeft(comment.Text, "/")), 2)
+		if len(fields) > 0 && strings.ToLower(fields[0]) == attribute {
+			return true
+		}
+	}
+
+	return false
+}
+
+// withTags returns the comments of an operation, with the @Tags of the package unless the operation has its own.
+func (defaults *packageDefaults) withTags(comments []*ast.Comment) []*ast.Comment {
+	if defaults == nil || len(defaults.tagComments) == 0 || hasAttribute(&ast.CommentGroup{List: comments}, tagsAttr) {
+		return comments
+	}
+
+	return append(append([]*ast.Comment{}, comments...), defaults.tagComments...)
+}
+
+// apply sets the defaults which operation doesn't declare itself and prefixes its router paths.
+func (defaults *packageDefaults) apply(operation *Operation) {
+	if defaults == nil {
+		return
+	}
+
+	if len(operation.Tags) == 0 {
+		operation.Tags = append(operation.Tags, defaults.operation.Tags...)
+	}
+
+	if len(operation.Consumes) == 0 {
+		operation.Consumes = append(operation.Consumes, defaults.operation.Consumes...)
+	}
+
+	if len(operation.Produces) == 0 {
+		operation.Produces = append(operation.Produces, defaults.operation.Produces...)
+	}
+
+	// an empty, non nil security is an explicit opt out
+	if operation.Security == nil && defaults.operation.Security != nil {
+		operation.Security = append([]map[string][]string{}, defaults.operation.Security...)
+	}
+
+	if defaults.routerPrefix != "" {
+		prefix := strings.TrimSuffix(defaults.routerPrefix, "/")
+
+		for i, route := range operation.RouterProperties {
+			if route.Path == "/" && prefix != "" {
+				operation.RouterProperties[i].Path = prefix
+			} else {
+				operation.RouterProperties[i].Path = prefix + route.Path
+			}
+		}
+	}
+}
@@ -0,0 +1,269 @@
+package swag
+
+import (
+	"encoding/json"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseOperationScope(t *testing.T) {
+	t.Parallel()
+
+	scope, rest, err := parseOperationScope(`tag=admin,users path=/admin method=post,Put 403 {string} string "forbidden"`)
+	require.NoError(t, err)
+	assert.Equal(t, operationScope{
+		tags:       []string{"admin", "users"},
+		pathPrefix: "/admin",
+		methods:    []string{"POST", "PUT"},
+	}, scope)
+	assert.Equal(t, `403 {string} string "forbidden"`, rest)
+
+	scope, rest, err = parseOperationScope(`500 {string} string "a=b"`)
+	require.NoError(t, err)
+	assert.Equal(t, operationScope{}, scope)
+	assert.Equal(t, `500 {string} string "a=b"`, rest)
+
+	_, _, err = parseOperationScope(`status=500 {string} string "error"`)
+	assert.EqualError(t, err, "unknown scope status, expected tag, path or method")
+}
+
+func TestParser_DefaultResponses(t *testing.T) {
+	t.Parallel()
+
+	src := `
+package api
+
+type APIError struct {
+	Message string
+}
+
+// @Summary List pets
+// @Tags pets
+// @Success 200 {string} string "ok"
+// @Failure 400 {string} string "invalid filter"
+// @Router /pets [get]
+func ListPets(){
+}
+
+// @Summary Delete a user
+// @Tags admin
+// @Success 204
+// @Header 204 {string} X-Request-Id "the request"
+// @Router /admin/users/{id} [delete]
+func DeleteUser(){
+}
+`
+	p := New()
+	err := parseGeneralAPIInfo(p, []string{
+		`@responses.InternalError {object} api.APIError "internal server error"`,
+		`@default.header all {string} X-Request-Id "request id"`,
+		`@default.response 400,401 {object} api.APIError "bad request"`,
+		`@default.response 500 ref:InternalError`,
+		`@default.response tag=admin method=delete 403 {object} api.APIError "forbidden"`,
+		`@default.response path=/pets method=post 409 {object} api.APIError "conflict"`,
+	})
+	require.NoError(t, err)
+
+	err = p.packages.ParseFile("api", "api/api.go", src, ParseAll)
+	require.NoError(t, err)
+
+	_, err = p.packages.ParseTypes()
+	require.NoError(t, err)
+
+	err = p.parseReusableDefinitions()
+	require.NoError(t, err)
+
+	err = p.packages.RangeFiles(p.ParseRouterAPIInfo)
+	require.NoError(t, err)
+
+	b, _ := json.MarshalIndent(p.swagger.Paths, "", "    ")
+
+	expected := `{
+    "/admin/users/{id}": {
+        "delete": {
+            "tags": [
+                "admin"
+            ],
+            "summary": "Delete a user",
+            "responses": {
+                "204": {
+                    "description": "No Content",
+                    "headers": {
+                        "X-Request-Id": {
+                            "type": "string",
+                            "description": "the request"
+                        }
+                    }
+                },
+                "400": {
+                    "description": "bad request",
+                    "schema": {
+                        "$ref": "#/definitions/api.APIError"
+                    },
+                    "headers": {
+                        "X-Request-Id": {
+                            "type": "string",
+                            "description": "request id"
+                        }
+                    }
+                },
+                "401": {
+                    "description": "bad request",
+                    "schema": {
+                        "$ref": "#/definitions/api.APIError"
+                    },
+                    "headers": {
+                        "X-Request-Id": {
+                            "type": "string",
+                            "description": "request id"
+                        }
+                    }
+                },
+                "403": {
+                    "description": "forbidden",
+                    "schema": {
+                        "$ref": "#/definitions/api.APIError"
+                    },
+                    "headers": {
+                        "X-Request-Id": {
+                            "type": "string",
+                            "description": "request id"
+                        }
+                    }
+                },
+                "500": {
+                    "$ref": "#/responses/InternalError"
+                }
+            }
+        }
+    },
+    "/pets": {
+        "get": {
+            "tags": [
+                "pets"
+            ],
+            "summary": "List pets",
+            "responses": {
+                "200": {
+                    "description": "ok",
+                    "schema": {
+                        "type": "string"
+                    },
+                    "headers": {
+                        "X-Request-Id": {
+                            "type": "string",
+                            "description": "request id"
+                        }
+                    }
+                },
+                "400": {
+                    "description": "invalid filter",
+                    "schema": {
+                        "type": "string"
+                    },
+                    "headers": {
+                        "X-Request-Id": {
+                            "type": "string",
+                            "description": "request id"
+                        }
+                    }
+                },
+                "401": {
+                    "description": "bad request",
+                    "schema": {
+                        "$ref": "#/definitions/api.APIError"
+                    },
+                    "headers": {
+                        "X-Request-Id": {
+                            "type": "string",
+                            "description": "request id"
+                        }
+                    }
+                },
+                "500": {
+                    "$ref": "#/responses/InternalError"
+                }
+            }
+        }
+    }
+}`
+	assert.Equal(t, expected, string(b))
+
+	p = New()
+	err = parseGeneralAPIInfo(p, []string{`@default.header oops {string} X-Request-Id "request id"`})
+	require.NoError(t, err)
+	assert.EqualError(t, p.parseReusableDefinitions(), `@default.header: can not parse response comment "oops {string} X-Request-Id "request id""`)
+}
+
+func TestParser_PackageDefaults(t *testing.T) {
+	t.Parallel()
+
+	doc := `
+// Package pets handles the pets of the store.
+//
+// @Tags pets
+// @Accept json
+// @Produce json,xml
+// @Security ApiKeyAuth
+// @Router /v1/pets/
+package pets
+`
+	src := `
+package pets
+
+// @Summary List pets
+// @Router / [get]
+func List(){
+}
+
+// @Summary Upload a picture
+// @Tags pictures
+// @Accept mpfd
+// @Security none
+// @Router /{id}/picture [post]
+func Upload(){
+}
+`
+	p := New()
+	require.NoError(t, p.packages.ParseFile("pets", "pets/doc.go", doc, ParseAll))
+	require.NoError(t, p.packages.ParseFile("pets", "pets/pets.go", src, ParseAll))
+	require.NoError(t, p.packages.RangeFiles(p.ParseRouterAPIInfo))
+
+	list := p.swagger.Paths.Paths["/v1/pets"].Get
+	require.NotNil(t, list)
+	assert.Equal(t, []string{"pets"}, list.Tags)
+	assert.Equal(t, []string{"application/json"}, list.Consumes)
+	assert.Equal(t, []string{"application/json", "text/xml"}, list.Produces)
+	assert.Equal(t, []map[string][]string{{"ApiKeyAuth": {}}}, list.Security)
+
+	upload := p.swagger.Paths.Paths["/v1/pets/{id}/picture"].Post
+	require.NotNil(t, upload)
+	assert.Equal(t, []string{"pictures"}, upload.Tags)
+	assert.Equal(t, []string{"multipart/form-data"}, upload.Consumes)
+	assert.Equal(t, []string{"application/json", "text/xml"}, upload.Produces)
+	assert.Equal(t, []map[string][]string{}, upload.Security)
+
+	operations := p.Operations()
+	require.Len(t, operations, 2)
+	assert.Equal(t, "/v1/pets", operations[0].Path)
+
+	// the tags of the package filter its operations
+	p = New(SetTags("pets"))
+	require.NoError(t, p.packages.ParseFile("pets", "pets/doc.go", doc, ParseAll))
+	require.NoError(t, p.packages.ParseFile("pets", "pets/pets.go", src, ParseAll))
+	require.NoError(t, p.packages.RangeFiles(p.ParseRouterAPIInfo))
+
+	assert.Len(t, p.swagger.Paths.Paths, 1)
+	assert.NotNil(t, p.swagger.Paths.Paths["/v1/pets"].Get)
+
+	p = New()
+	require.NoError(t, p.packages.ParseFile("pets", "pets/doc.go", "// @Router pets\npackage pets\n", ParseAll))
+	require.NoError(t, p.packages.ParseFile("pets", "pets/pets.go", src, ParseAll))
+
+	path, _ := filepath.Abs("pets/doc.go")
+	assert.EqualError(t, p.packages.RangeFiles(p.ParseRouterAPIInfo),
+		"ParseComment error in file "+path+" :can not parse router prefix \"@Router pets\"")
+}
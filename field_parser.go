@@ -384,9 +384,12 @@ func (ps *tagBaseFieldParser) complementSchema(schema *spec.Schema, types []stri
 		schema.Default = value
 	}
 
-	schema.Example = field.exampleValue
+	// keep the example and format of the type of the field, e.g. of a well-known type
+	if field.exampleValue != nil {
+		schema.Example = field.exampleValue
+	}
 
-	if field.schemaType != ARRAY {
+	if field.schemaType != ARRAY && field.formatType != "" {
 		schema.Format = field.formatType
 	}
 
@@ -432,7 +435,9 @@ func (ps *tagBaseFieldParser) complementSchema(schema *spec.Schema, types []stri
 		schema.UniqueItems = field.unique
 
 		eleSchema = schema.Items.Schema
-		eleSchema.Format = field.formatType
+		if field.formatType != "" {
+			eleSchema.Format = field.formatType
+		}
 	}
 
 	eleSchema.Maximum = field.maximum
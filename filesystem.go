@@ -0,0 +1,31 @@
+package swag
+
+import (
+	"io/fs"
+	"os"
+	"path"
+	"path/filepath"
+)
+
+// readDir reads the named directory from fsys, or from the OS file system if fsys is nil.
+func readDir(fsys fs.FS, name string) ([]fs.DirEntry, error) {
+	if fsys == nil {
+		return os.ReadDir(name)
+	}
+
+	return fs.ReadDir(fsys, fsPath(name))
+}
+
+// readFile reads the named file from fsys, or from the OS file system if fsys is nil.
+func readFile(fsys fs.FS, name string) ([]byte, error) {
+	if fsys == nil {
+		return os.ReadFile(name)
+	}
+
+	return fs.ReadFile(fsys, fsPath(name))
+}
+
+// fsPath converts an OS path to the slash separated, cleaned form fs.FS expects.
+func fsPath(name string) string {
+	return path.Clean(filepath.ToSlash(name))
+}
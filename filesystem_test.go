@@ -0,0 +1,116 @@
+package swag
+
+import (
+	"testing"
+	"testing/fstest"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+var memFS = fstest.MapFS{
+	"go.mod": {Data: []byte("module example.com/petstore\n\ngo 1.18\n")},
+	"main.go": {Data: []byte(`package main
+
+// @title Petstore
+// @version 1.0
+// @description.markdown
+// @BasePath /api
+func main() {}
+`)},
+	"api/pets.go": {Data: []byte(`package api
+
+import "example.com/petstore/model"
+
+// GetPet godoc
+// @Summary Get a pet
+// @Tags pets
+// @Param id path int true "Pet ID"
+// @Success 200 {object} model.Pet
+// @x-codeSamples file
+// @Router /pets/{id} [get]
+func GetPet() {}
+`)},
+	"api/pets_test.go": {Data: []byte(`package api
+
+// @Router /ignored [get]
+func TestIgnored() {}
+`)},
+	"model/pet.go": {Data: []byte(`package model
+
+type Pet struct {
+	ID   int    ` + "`json:\"id\"`" + `
+	Name string ` + "`json:\"name\"`" + `
+}
+`)},
+	"docs/docs.go":            {Data: []byte("package docs\n\nthis is not Go\n")},
+	"markdown/api.md":         {Data: []byte("Petstore served from memory")},
+	"examples/Get a pet.json": {Data: []byte(`{"lang": "go", "source": "GetPet()"}`)},
+}
+
+func TestParser_FileSystem(t *testing.T) {
+	p := New(
+		SetFileSystem(memFS),
+		SetMarkdownFileDirectory("markdown"),
+		SetCodeExamplesDirectory("examples"),
+	)
+
+	require.NoError(t, p.ParseAPIMultiSearchDir([]string{"."}, "main.go", 100))
+
+	swagger := p.GetSwagger()
+	assert.Equal(t, "Petstore", swagger.Info.Title)
+	assert.Equal(t, "Petstore served from memory", swagger.Info.Description)
+	assert.Equal(t, "/api", swagger.BasePath)
+
+	require.Contains(t, swagger.Paths.Paths, "/pets/{id}")
+	assert.NotContains(t, swagger.Paths.Paths, "/ignored")
+
+	op := swagger.Paths.Paths["/pets/{id}"].Get
+	require.NotNil(t, op)
+	assert.Equal(t, "#/definitions/model.Pet", op.Responses.StatusCodeResponses[200].Schema.Ref.String())
+	assert.Equal(t, map[string]interface{}{"lang": "go", "source": "GetPet()"}, op.Extensions["x-codeSamples"])
+
+	require.Contains(t, swagger.Definitions, "model.Pet")
+	assert.Contains(t, swagger.Definitions["model.Pet"].Properties, "name")
+}
+
+func TestParser_FileSystemSubDir(t *testing.T) {
+	p := New(SetFileSystem(memFS))
+
+	pkgName, err := p.getPkgName("./api")
+	require.NoError(t, err)
+	assert.Equal(t, "example.com/petstore/api", pkgName)
+
+	pkgName, err = p.getPkgName(".")
+	require.NoError(t, err)
+	assert.Equal(t, "example.com/petstore", pkgName)
+
+	pkgName, err = New(SetFileSystem(fstest.MapFS{"a/b.go": {}})).getPkgName("a")
+	require.NoError(t, err)
+	assert.Equal(t, "a", pkgName)
+
+	err = New(SetFileSystem(memFS)).ParseAPIMultiSearchDir([]string{"."}, "missing.go", 100)
+	assert.Error(t, err)
+}
+
+func TestParser_FileSystemInvalidSearchDir(t *testing.T) {
+	fsys := fstest.MapFS{
+		"src/main.go": {Data: []byte(`package main
+
+// @title Petstore
+func main() {}
+`)},
+	}
+
+	pkgName, err := New(SetFileSystem(fsys)).getPkgName("/src")
+	require.NoError(t, err)
+	assert.Equal(t, "/src", pkgName)
+
+	err = New(SetFileSystem(fsys)).ParseAPIMultiSearchDir([]string{"/src"}, "main.go", 100)
+	assert.EqualError(t, err, "search dir /src is not a valid path of the file system")
+
+	err = New(SetFileSystem(fsys)).ParseAPIMultiSearchDir([]string{"../src"}, "main.go", 100)
+	assert.EqualError(t, err, "search dir ../src is not a valid path of the file system")
+
+	require.NoError(t, New(SetFileSystem(fsys)).ParseAPIMultiSearchDir([]string{"src"}, "main.go", 100))
+}
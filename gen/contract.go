@@ -0,0 +1,693 @@
+package gen
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"sort"
+	"strings"
+	"text/template"
+
+	"github.com/go-openapi/spec"
+	"github.com/swaggo/swag"
+)
+
+// contractCase is a single request/response pair rendered into the generated contract tests.
+type contractCase struct {
+	Name            string
+	Method          string
+	Target          string
+	Header          [][2]string
+	Body            string
+	WantStatus      int
+	WantContentType string
+	WantSchema      string
+}
+
+// setHeader sets a request header, replacing a header of the same canonical name. The generated cases
+// declare headers as a map literal, which can't repeat a key.
+func (tc *contractCase) setHeader(name, value string) {
+	name = http.CanonicalHeaderKey(name)
+
+	for i := range tc.Header {
+		if tc.Header[i][0] == name {
+			tc.Header[i][1] = value
+
+			return
+		}
+	}
+
+	tc.Header = append(tc.Header, [2]string{name, value})
+}
+
+func (g *Gen) renderContractTests(config *Config, swagger *spec.Swagger) ([]Artifact, error) {
+	packageName, err := docsPackageName(config)
+	if err != nil {
+		return nil, err
+	}
+
+	packageName += "_test"
+
+	buffer := &bytes.Buffer{}
+
+	err = g.writeContractTestsTo(packageName, buffer, swagger, config)
+	if err != nil {
+		return nil, err
+	}
+
+	casesBuffer := &bytes.Buffer{}
+
+	err = executeContractTemplate(contractCasesTemplate, casesBuffer, packageName, config, nil, "")
+	if err != nil {
+		return nil, err
+	}
+
+	return []Artifact{
+		{
+			Name:       instanceFileName(config, "swagger_contract_test.go"),
+			OutputType: "contract",
+			Content:    buffer.Bytes(),
+		},
+		{
+			// the cases file belongs to the user once it exists, never overwrite it
+			Name:         instanceFileName(config, "swagger_contract_cases_test.go"),
+			OutputType:   "contract",
+			Content:      g.formatSource(casesBuffer.Bytes()),
+			KeepExisting: true,
+		},
+	}, nil
+}
+
+func (g *Gen) writeContractTestsTo(packageName string, output io.Writer, swagger *spec.Swagger, config *Config) error {
+	definitions, err := g.json(swagger.Definitions)
+	if err != nil {
+		return err
+	}
+
+	cases, err := buildContractCases(swagger)
+	if err != nil {
+		return err
+	}
+
+	buffer := &bytes.Buffer{}
+
+	err = executeContractTemplate(contractTemplate, buffer, packageName, config, cases, string(definitions))
+	if err != nil {
+		return err
+	}
+
+	_, err = output.Write(g.formatSource(buffer.Bytes()))
+
+	return err
+}
+
+func executeContractTemplate(text string, output *bytes.Buffer, packageName string, config *Config, cases []contractCase, definitions string) error {
+	tpl, err := template.New("contract").Parse(text)
+	if err != nil {
+		return err
+	}
+
+	var suffix string
+	if config.InstanceName != "" && config.InstanceName != swag.Name {
+		suffix = config.InstanceName
+	}
+
+	return tpl.Execute(output, struct {
+		PackageName string
+		Suffix      string
+		Cases       []contractCase
+		Definitions string
+	}{
+		PackageName: packageName,
+		Suffix:      suffix,
+		Cases:       cases,
+		Definitions: definitions,
+	})
+}
+
+// buildContractCases creates one case per documented operation, in path and method order.
+func buildContractCases(swagger *spec.Swagger) ([]contractCase, error) {
+	paths := make([]string, 0)
+	if swagger.Paths != nil {
+		for p := range swagger.Paths.Paths {
+			paths = append(paths, p)
+		}
+	}
+
+	sort.Strings(paths)
+
+	var cases []contractCase
+
+	for _, p := range paths {
+		item := swagger.Paths.Paths[p]
+
+		for _, method := range []string{http.MethodGet, http.MethodPut, http.MethodPost, http.MethodDelete,
+			http.MethodOptions, http.MethodHead, http.MethodPatch} {
+			op := operationForMethod(&item, method)
+			if op == nil {
+				continue
+			}
+
+			tc, err := buildContractCase(swagger, p, method, op)
+			if err != nil {
+				return nil, fmt.Errorf("%s %s: %w", method, p, err)
+			}
+
+			cases = append(cases, tc)
+		}
+	}
+
+	return cases, nil
+}
+
+func operationForMethod(item *spec.PathItem, method string) *spec.Operation {
+	switch method {
+	case http.MethodGet:
+		return item.Get
+	case http.MethodPut:
+		return item.Put
+	case http.MethodPost:
+		return item.Post
+	case http.MethodDelete:
+		return item.Delete
+	case http.MethodOptions:
+		return item.Options
+	case http.MethodHead:
+		return item.Head
+	case http.MethodPatch:
+		return item.Patch
+	}
+
+	return nil
+}
+
+func buildContractCase(swagger *spec.Swagger, pathTemplate, method string, op *spec.Operation) (contractCase, error) {
+	tc := contractCase{
+		Name:   method + " " + pathTemplate,
+		Method: method,
+	}
+
+	target := strings.TrimSuffix(swagger.BasePath, "/") + pathTemplate
+	query, form := url.Values{}, url.Values{}
+
+	var contentType string
+
+	for _, param := range op.Parameters {
+		if ref := param.Ref.String(); ref != "" {
+			param = swagger.Parameters[strings.TrimPrefix(ref, "#/parameters/")]
+		}
+
+		switch param.In {
+		case "path":
+			target = strings.ReplaceAll(target, "{"+param.Name+"}", url.PathEscape(simpleExample(&param)))
+		case "query":
+			if param.Required || param.Example != nil {
+				query.Set(param.Name, simpleExample(&param))
+			}
+		case "header":
+			if param.Required || param.Example != nil {
+				tc.setHeader(param.Name, simpleExample(&param))
+			}
+		case "formData":
+			if param.Type != "file" && (param.Required || param.Example != nil) {
+				form.Set(param.Name, simpleExample(&param))
+			}
+		case "body":
+			body, err := bodyExample(swagger, param.Schema)
+			if err != nil {
+				return tc, err
+			}
+
+			tc.Body = body
+			contentType = firstOf(op.Consumes, swagger.Consumes, "application/json")
+		}
+	}
+
+	if len(form) > 0 {
+		tc.Body = form.Encode()
+		contentType = "application/x-www-form-urlencoded"
+	}
+
+	// the encoding of the body takes precedence over a documented Content-Type header
+	if contentType != "" {
+		tc.setHeader("Content-Type", contentType)
+	}
+
+	if len(query) > 0 {
+		target += "?" + query.Encode()
+	}
+
+	tc.Target = target
+
+	code, response := expectedResponse(swagger, op.Responses)
+	tc.WantStatus = code
+
+	if response == nil || response.Schema == nil {
+		return tc, nil
+	}
+
+	tc.WantContentType = firstOf(op.Produces, swagger.Produces, "application/json")
+	if !strings.HasSuffix(tc.WantContentType, "json") {
+		return tc, nil
+	}
+
+	schema, err := json.Marshal(response.Schema)
+	if err != nil {
+		return tc, err
+	}
+
+	tc.WantSchema = string(schema)
+
+	return tc, nil
+}
+
+// expectedResponse picks the lowest documented 2xx response, falling back to the lowest documented code.
+// A reference to a reusable response is resolved.
+func expectedResponse(swagger *spec.Swagger, responses *spec.Responses) (int, *spec.Response) {
+	if responses == nil || len(responses.StatusCodeResponses) == 0 {
+		return 0, nil
+	}
+
+	codes := make([]int, 0, len(responses.StatusCodeResponses))
+	for code := range responses.StatusCodeResponses {
+		codes = append(codes, code)
+	}
+
+	sort.Ints(codes)
+
+	code := codes[0]
+
+	for _, c := range codes {
+		if c >= 200 && c < 300 {
+			code = c
+
+			break
+		}
+	}
+
+	response := responses.StatusCodeResponses[code]
+	if ref := response.Ref.String(); ref != "" {
+		response = swagger.Responses[strings.TrimPrefix(ref, "#/responses/")]
+	}
+
+	return code, &response
+}
+
+func firstOf(mimeTypes []string, fallback []string, def string) string {
+	if len(mimeTypes) > 0 {
+		return mimeTypes[0]
+	}
+
+	if len(fallback) > 0 {
+		return fallback[0]
+	}
+
+	return def
+}
+
+// simpleExample returns a string value for a non-body parameter, preferring
+// the documented example, then the default, then the first enum value.
+func simpleExample(param *spec.Parameter) string {
+	switch {
+	case param.Example != nil:
+		return fmt.Sprint(param.Example)
+	case param.Default != nil:
+		return fmt.Sprint(param.Default)
+	case len(param.Enum) > 0:
+		return fmt.Sprint(param.Enum[0])
+	}
+
+	typeName := param.Type
+	if typeName == swag.ARRAY && param.Items != nil {
+		if len(param.Items.Enum) > 0 {
+			return fmt.Sprint(param.Items.Enum[0])
+		}
+
+		typeName = param.Items.Type
+	}
+
+	return fmt.Sprint(primitiveExample(typeName, param.Format))
+}
+
+func primitiveExample(typeName, format string) interface{} {
+	switch typeName {
+	case swag.INTEGER:
+		return 1
+	case swag.NUMBER:
+		return 1.5
+	case swag.BOOLEAN:
+		return true
+	}
+
+	switch format {
+	case "date-time":
+		return "2006-01-02T15:04:05Z"
+	case "date":
+		return "2006-01-02"
+	case "email":
+		return "user@example.com"
+	case "uuid":
+		return "00000000-0000-0000-0000-000000000000"
+	}
+
+	return "string"
+}
+
+func bodyExample(swagger *spec.Swagger, schema *spec.Schema) (string, error) {
+	if schema == nil {
+		return "", nil
+	}
+
+	var value interface{}
+	if schema.Example != nil {
+		value = schema.Example
+	} else {
+		value = schemaExample(swagger, schema, map[string]bool{})
+	}
+
+	if s, ok := value.(string); ok && !swaggerHasType(schema, swag.STRING) {
+		return s, nil
+	}
+
+	b, err := json.Marshal(value)
+	if err != nil {
+		return "", err
+	}
+
+	return string(b), nil
+}
+
+func swaggerHasType(schema *spec.Schema, typeName string) bool {
+	return len(schema.Type) > 0 && schema.Type[0] == typeName
+}
+
+// schemaExample synthesizes a value matching schema. seen guards against recursive definitions.
+func schemaExample(swagger *spec.Swagger, schema *spec.Schema, seen map[string]bool) interface{} {
+	if schema.Example != nil {
+		return schema.Example
+	}
+
+	if ref := schema.Ref.String(); ref != "" {
+		name := strings.TrimPrefix(ref, "#/definitions/")
+		definition, ok := swagger.Definitions[name]
+		if !ok || seen[name] {
+			return nil
+		}
+
+		seen[name] = true
+		defer delete(seen, name)
+
+		return schemaExample(swagger, &definition, seen)
+	}
+
+	if len(schema.AllOf) > 0 {
+		merged := map[string]interface{}{}
+
+		for i := range schema.AllOf {
+			if part, ok := schemaExample(swagger, &schema.AllOf[i], seen).(map[string]interface{}); ok {
+				for k, v := range part {
+					merged[k] = v
+				}
+			}
+		}
+
+		return merged
+	}
+
+	if len(schema.Enum) > 0 {
+		return schema.Enum[0]
+	}
+
+	if schema.Default != nil {
+		return schema.Default
+	}
+
+	typeName := swag.OBJECT
+	if len(schema.Type) > 0 {
+		typeName = schema.Type[0]
+	}
+
+	switch typeName {
+	case swag.ARRAY:
+		if schema.Items == nil || schema.Items.Schema == nil {
+			return []interface{}{}
+		}
+
+		return []interface{}{schemaExample(swagger, schema.Items.Schema, seen)}
+	case swag.OBJECT:
+		result := map[string]interface{}{}
+
+		for name, property := range schema.Properties {
+			property := property
+			result[name] = schemaExample(swagger, &property, seen)
+		}
+
+		return result
+	}
+
+	return primitiveExample(typeName, schema.Format)
+}
+
+var contractTemplate = `// Code generated by swaggo/swag. DO NOT EDIT
+package {{ .PackageName }}
+
+import (
+	"encoding/json"
+	"fmt"
+	"math"
+	"mime"
+	"net/http/httptest"
+	"sort"
+	"strings"
+	"testing"
+)
+
+// contractCase{{ .Suffix }} is a request sent to the handler under test together with the documented response.
+type contractCase{{ .Suffix }} struct {
+	Name            string
+	Method          string
+	Target          string
+	Header          map[string]string
+	Body            string
+	WantStatus      int
+	WantContentType string
+	WantSchema      string
+}
+
+var generatedContractCases{{ .Suffix }} = []contractCase{{ .Suffix }}{
+{{- range .Cases }}
+	{
+		Name:   {{ printf "%q" .Name }},
+		Method: {{ printf "%q" .Method }},
+		Target: {{ printf "%q" .Target }},
+		{{- if .Header }}
+		Header: map[string]string{
+			{{- range .Header }}
+			{{ printf "%q" (index . 0) }}: {{ printf "%q" (index . 1) }},
+			{{- end }}
+		},
+		{{- end }}
+		{{- if .Body }}
+		Body: {{ printf "%q" .Body }},
+		{{- end }}
+		WantStatus: {{ .WantStatus }},
+		{{- if .WantContentType }}
+		WantContentType: {{ printf "%q" .WantContentType }},
+		{{- end }}
+		{{- if .WantSchema }}
+		WantSchema: {{ printf "%q" .WantSchema }},
+		{{- end }}
+	},
+{{- end }}
+}
+
+const contractDefinitions{{ .Suffix }} = {{ printf "%q" .Definitions }}
+
+func TestContract{{ .Suffix }}(t *testing.T) {
+	handler := contractHandler{{ .Suffix }}(t)
+
+	var definitions map[string]interface{}
+	if err := json.Unmarshal([]byte(contractDefinitions{{ .Suffix }}), &definitions); err != nil {
+		t.Fatal(err)
+	}
+
+	for _, tc := range mergeContractCases{{ .Suffix }}(generatedContractCases{{ .Suffix }}, contractCases{{ .Suffix }}) {
+		tc := tc
+
+		t.Run(tc.Name, func(t *testing.T) {
+			req := httptest.NewRequest(tc.Method, tc.Target, strings.NewReader(tc.Body))
+			for key, value := range tc.Header {
+				req.Header.Set(key, value)
+			}
+
+			rec := httptest.NewRecorder()
+			handler.ServeHTTP(rec, req)
+
+			if tc.WantStatus != 0 && rec.Code != tc.WantStatus {
+				t.Fatalf("status: want %d, got %d", tc.WantStatus, rec.Code)
+			}
+
+			if tc.WantContentType != "" {
+				mediaType, _, _ := mime.ParseMediaType(rec.Header().Get("Content-Type"))
+				if mediaType != tc.WantContentType {
+					t.Errorf("Content-Type: want %s, got %q", tc.WantContentType, rec.Header().Get("Content-Type"))
+				}
+			}
+
+			if tc.WantSchema == "" {
+				return
+			}
+
+			var schema map[string]interface{}
+			if err := json.Unmarshal([]byte(tc.WantSchema), &schema); err != nil {
+				t.Fatalf("invalid WantSchema: %v", err)
+			}
+
+			var body interface{}
+			if err := json.Unmarshal(rec.Body.Bytes(), &body); err != nil {
+				t.Fatalf("response body is not valid JSON: %v", err)
+			}
+
+			for _, problem := range checkContractSchema{{ .Suffix }}(definitions, schema, body, "$") {
+				t.Error(problem)
+			}
+		})
+	}
+}
+
+// mergeContractCases{{ .Suffix }} replaces generated cases by custom cases with the same Name and appends the rest.
+func mergeContractCases{{ .Suffix }}(generated, custom []contractCase{{ .Suffix }}) []contractCase{{ .Suffix }} {
+	byName := make(map[string]int, len(generated))
+	result := append([]contractCase{{ .Suffix }}{}, generated...)
+
+	for i, tc := range result {
+		byName[tc.Name] = i
+	}
+
+	for _, tc := range custom {
+		if i, ok := byName[tc.Name]; ok {
+			result[i] = tc
+
+			continue
+		}
+
+		result = append(result, tc)
+	}
+
+	return result
+}
+
+// checkContractSchema{{ .Suffix }} reports every place where value does not have the shape described by schema.
+func checkContractSchema{{ .Suffix }}(definitions, schema map[string]interface{}, value interface{}, path string) []string {
+	if ref, ok := schema["$ref"].(string); ok {
+		definition, ok := definitions[strings.TrimPrefix(ref, "#/definitions/")].(map[string]interface{})
+		if !ok {
+			return []string{fmt.Sprintf("%s: unknown reference %s", path, ref)}
+		}
+
+		return checkContractSchema{{ .Suffix }}(definitions, definition, value, path)
+	}
+
+	var problems []string
+
+	if allOf, ok := schema["allOf"].([]interface{}); ok {
+		for _, part := range allOf {
+			if partSchema, ok := part.(map[string]interface{}); ok {
+				problems = append(problems, checkContractSchema{{ .Suffix }}(definitions, partSchema, value, path)...)
+			}
+		}
+	}
+
+	if value == nil {
+		return problems
+	}
+
+	schemaType, _ := schema["type"].(string)
+
+	switch schemaType {
+	case "object":
+		object, ok := value.(map[string]interface{})
+		if !ok {
+			return append(problems, fmt.Sprintf("%s: want object, got %T", path, value))
+		}
+
+		if required, ok := schema["required"].([]interface{}); ok {
+			for _, name := range required {
+				if _, ok := object[fmt.Sprint(name)]; !ok {
+					problems = append(problems, fmt.Sprintf("%s: missing required property %q", path, name))
+				}
+			}
+		}
+
+		properties, _ := schema["properties"].(map[string]interface{})
+		additional, _ := schema["additionalProperties"].(map[string]interface{})
+
+		keys := make([]string, 0, len(object))
+		for key := range object {
+			keys = append(keys, key)
+		}
+
+		sort.Strings(keys)
+
+		for _, key := range keys {
+			if property, ok := properties[key].(map[string]interface{}); ok {
+				problems = append(problems, checkContractSchema{{ .Suffix }}(definitions, property, object[key], path+"."+key)...)
+			} else if additional != nil {
+				problems = append(problems, checkContractSchema{{ .Suffix }}(definitions, additional, object[key], path+"."+key)...)
+			}
+		}
+	case "array":
+		array, ok := value.([]interface{})
+		if !ok {
+			return append(problems, fmt.Sprintf("%s: want array, got %T", path, value))
+		}
+
+		if items, ok := schema["items"].(map[string]interface{}); ok {
+			for i, item := range array {
+				problems = append(problems, checkContractSchema{{ .Suffix }}(definitions, items, item, fmt.Sprintf("%s[%d]", path, i))...)
+			}
+		}
+	case "string":
+		if _, ok := value.(string); !ok {
+			problems = append(problems, fmt.Sprintf("%s: want string, got %T", path, value))
+		}
+	case "integer":
+		if number, ok := value.(float64); !ok || number != math.Trunc(number) {
+			problems = append(problems, fmt.Sprintf("%s: want integer, got %v", path, value))
+		}
+	case "number":
+		if _, ok := value.(float64); !ok {
+			problems = append(problems, fmt.Sprintf("%s: want number, got %T", path, value))
+		}
+	case "boolean":
+		if _, ok := value.(bool); !ok {
+			problems = append(problems, fmt.Sprintf("%s: want boolean, got %T", path, value))
+		}
+	}
+
+	return problems
+}
+`
+
+var contractCasesTemplate = `package {{ .PackageName }}
+
+import (
+	"net/http"
+	"testing"
+)
+
+// This file is created once by swag and never overwritten, edit it freely.
+
+// contractHandler{{ .Suffix }} returns the handler the generated contract tests are run against.
+func contractHandler{{ .Suffix }}(t *testing.T) http.Handler {
+	t.Skip("contractHandler{{ .Suffix }} is not implemented")
+
+	return nil
+}
+
+// contractCases{{ .Suffix }} replace generated cases with the same Name, other cases are run in addition.
+var contractCases{{ .Suffix }} = []contractCase{{ .Suffix }}{}
+`
@@ -25,14 +25,28 @@ var open = os.Open
 // DefaultOverridesFile is the location swagger will look for type overrides.
 const DefaultOverridesFile = ".swaggo"
 
-type genTypeWriter func(*Config, *spec.Swagger) error
+type genTypeRenderer func(*Config, *spec.Swagger) ([]Artifact, error)
+
+// Artifact is a file rendered for an output type.
+type Artifact struct {
+	// Name is the file name, relative to Config.OutputDir.
+	Name string
+
+	// OutputType is the output type the file was rendered for, e.g. "json".
+	OutputType string
+
+	Content []byte
+
+	// KeepExisting is set for files meant to be edited by hand, which Build never overwrites.
+	KeepExisting bool
+}
 
 // Gen presents a generate tool for swag.
 type Gen struct {
 	json          func(data interface{}) ([]byte, error)
 	jsonIndent    func(data interface{}) ([]byte, error)
 	jsonToYAML    func(data []byte) ([]byte, error)
-	outputTypeMap map[string]genTypeWriter
+	outputTypeMap map[string]genTypeRenderer
 	debug         Debugger
 }
 
@@ -52,11 +66,12 @@ func New() *Gen {
 		debug:      log.New(os.Stdout, "", log.LstdFlags),
 	}
 
-	gen.outputTypeMap = map[string]genTypeWriter{
-		"go":   gen.writeDocSwagger,
-		"json": gen.writeJSONSwagger,
-		"yaml": gen.writeYAMLSwagger,
-		"yml":  gen.writeYAMLSwagger,
+	gen.outputTypeMap = map[string]genTypeRenderer{
+		"go":       gen.renderDocSwagger,
+		"json":     gen.renderJSONSwagger,
+		"yaml":     gen.renderYAMLSwagger,
+		"yml":      gen.renderYAMLSwagger,
+		"contract": gen.renderContractTests,
 	}
 
 	return &gen
@@ -126,10 +141,80 @@ type Config struct {
 
 	// include only tags mentioned when searching, comma separated
 	Tags string
+
+	// PackageName is the package name of the generated docs.go. The default value is the base name of OutputDir.
+	PackageName string
+
+	// DocTemplateFile is the path of a Go text/template file used to generate docs.go instead of the
+	// built-in template. It receives the same data as the built-in template plus the full spec as .Swagger.
+	DocTemplateFile string
+
+	// Transformers modify the parsed spec in order before any file is written.
+	Transformers []Transformer
+
+	// EmbedSpec generates a docs.go embedding swagger.json with go:embed instead of inlining the spec.
+	// swagger.json is written even if it is not one of OutputTypes.
+	EmbedSpec bool
+
+	// ParamSeparator joins the names of nested struct fields expanded into params, "." if empty
+	ParamSeparator string
 }
 
 // Build builds swagger json file  for given searchDir and mainAPIFile. Returns json.
 func (g *Gen) Build(config *Config) error {
+	swagger, err := g.Parse(config)
+	if err != nil {
+		return err
+	}
+
+	if err := os.MkdirAll(config.OutputDir, os.ModePerm); err != nil {
+		return err
+	}
+
+	for _, outputType := range config.OutputTypes {
+		artifacts, err := g.Render(config, swagger, outputType)
+		if err != nil {
+			return err
+		}
+
+		for _, artifact := range artifacts {
+			fileName := path.Join(config.OutputDir, artifact.Name)
+
+			if artifact.KeepExisting {
+				if _, err := os.Stat(fileName); err == nil {
+					continue
+				}
+			}
+
+			err = g.writeFile(artifact.Content, fileName)
+			if err != nil {
+				return err
+			}
+
+			g.debug.Printf("create %s at %+v", artifact.Name, fileName)
+		}
+	}
+
+	return nil
+}
+
+// Generate parses the sources described by config and renders its output types without writing any file.
+func (g *Gen) Generate(config *Config) (*spec.Swagger, []Artifact, error) {
+	swagger, err := g.Parse(config)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	artifacts, err := g.Render(config, swagger, config.OutputTypes...)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	return swagger, artifacts, nil
+}
+
+// Parse parses the sources described by config and returns the spec, after applying config.Transformers.
+func (g *Gen) Parse(config *Config) (*spec.Swagger, error) {
 	if config.Debugger != nil {
 		g.debug = config.Debugger
 	}
@@ -140,7 +225,7 @@ func (g *Gen) Build(config *Config) error {
 	searchDirs := strings.Split(config.SearchDir, ",")
 	for _, searchDir := range searchDirs {
 		if _, err := os.Stat(searchDir); os.IsNotExist(err) {
-			return fmt.Errorf("dir: %s does not exist", searchDir)
+			return nil, fmt.Errorf("dir: %s does not exist", searchDir)
 		}
 	}
 
@@ -151,14 +236,14 @@ func (g *Gen) Build(config *Config) error {
 		if err != nil {
 			// Don't bother reporting if the default file is missing; assume there are no overrides
 			if !(config.OverridesFile == DefaultOverridesFile && os.IsNotExist(err)) {
-				return fmt.Errorf("could not open overrides file: %w", err)
+				return nil, fmt.Errorf("could not open overrides file: %w", err)
 			}
 		} else {
 			g.debug.Printf("Using overrides from %s", config.OverridesFile)
 
 			overrides, err = parseOverrides(overridesFile)
 			if err != nil {
-				return err
+				return nil, err
 			}
 		}
 	}
@@ -176,6 +261,7 @@ func (g *Gen) Build(config *Config) error {
 		swag.SetOverrides(overrides),
 		swag.ParseUsingGoList(config.ParseGoList),
 		swag.SetTags(config.Tags),
+		swag.SetNestedParamSeparator(config.ParamSeparator),
 	)
 
 	p.PropNamingStrategy = config.PropNamingStrategy
@@ -184,113 +270,155 @@ func (g *Gen) Build(config *Config) error {
 	p.RequiredByDefault = config.RequiredByDefault
 
 	if err := p.ParseAPIMultiSearchDir(searchDirs, config.MainAPIFile, config.ParseDepth); err != nil {
-		return err
+		return nil, err
 	}
 
 	swagger := p.GetSwagger()
 
-	if err := os.MkdirAll(config.OutputDir, os.ModePerm); err != nil {
-		return err
+	for _, transformer := range config.Transformers {
+		if err := transformer.Transform(swagger); err != nil {
+			return nil, fmt.Errorf("could not transform spec: %w", err)
+		}
 	}
 
-	for _, outputType := range config.OutputTypes {
+	return swagger, nil
+}
+
+// Render renders outputTypes for swagger. Unsupported output types are logged and skipped.
+func (g *Gen) Render(config *Config, swagger *spec.Swagger, outputTypes ...string) ([]Artifact, error) {
+	if config.InstanceName == "" {
+		config.InstanceName = swag.Name
+	}
+
+	var artifacts []Artifact
+
+	for _, outputType := range outputTypes {
 		outputType = strings.ToLower(strings.TrimSpace(outputType))
-		if typeWriter, ok := g.outputTypeMap[outputType]; ok {
-			if err := typeWriter(config, swagger); err != nil {
-				return err
+		if typeRenderer, ok := g.outputTypeMap[outputType]; ok {
+			rendered, err := typeRenderer(config, swagger)
+			if err != nil {
+				return nil, err
 			}
+
+			artifacts = append(artifacts, rendered...)
 		} else {
 			log.Printf("output type '%s' not supported", outputType)
 		}
 	}
 
-	return nil
+	return artifacts, nil
 }
 
-func (g *Gen) writeDocSwagger(config *Config, swagger *spec.Swagger) error {
-	var filename = "docs.go"
-
-	if config.InstanceName != swag.Name {
-		filename = config.InstanceName + "_" + filename
+// WriteTo renders outputType for swagger into w. When the output type renders several files, e.g. "go"
+// with EmbedSpec, only the file named after the output type is written.
+func (g *Gen) WriteTo(w io.Writer, outputType string, config *Config, swagger *spec.Swagger) error {
+	if _, ok := g.outputTypeMap[strings.ToLower(strings.TrimSpace(outputType))]; !ok {
+		return fmt.Errorf("output type '%s' not supported", outputType)
 	}
 
-	docFileName := path.Join(config.OutputDir, filename)
-
-	absOutputDir, err := filepath.Abs(config.OutputDir)
+	artifacts, err := g.Render(config, swagger, outputType)
 	if err != nil {
 		return err
 	}
 
-	packageName := filepath.Base(absOutputDir)
+	_, err = w.Write(artifacts[0].Content)
+
+	return err
+}
 
-	docs, err := os.Create(docFileName)
+func (g *Gen) renderDocSwagger(config *Config, swagger *spec.Swagger) ([]Artifact, error) {
+	packageName, err := docsPackageName(config)
 	if err != nil {
-		return err
+		return nil, err
 	}
-	defer docs.Close()
 
-	// Write doc
-	err = g.writeGoDoc(packageName, docs, swagger, config)
+	buffer := &bytes.Buffer{}
+
+	err = g.writeGoDoc(packageName, buffer, swagger, config)
 	if err != nil {
-		return err
+		return nil, err
 	}
 
-	g.debug.Printf("create docs.go at %+v", docFileName)
+	artifacts := []Artifact{{
+		Name:       instanceFileName(config, "docs.go"),
+		OutputType: "go",
+		Content:    buffer.Bytes(),
+	}}
 
-	return nil
-}
-
-func (g *Gen) writeJSONSwagger(config *Config, swagger *spec.Swagger) error {
-	var filename = "swagger.json"
+	if config.EmbedSpec && !hasOutputType(config.OutputTypes, "json") {
+		jsonArtifacts, err := g.renderJSONSwagger(config, swagger)
+		if err != nil {
+			return nil, err
+		}
 
-	if config.InstanceName != swag.Name {
-		filename = config.InstanceName + "_" + filename
+		artifacts = append(artifacts, jsonArtifacts...)
 	}
 
-	jsonFileName := path.Join(config.OutputDir, filename)
+	return artifacts, nil
+}
 
+func (g *Gen) renderJSONSwagger(config *Config, swagger *spec.Swagger) ([]Artifact, error) {
 	b, err := g.jsonIndent(swagger)
 	if err != nil {
-		return err
+		return nil, err
 	}
 
-	err = g.writeFile(b, jsonFileName)
+	return []Artifact{{
+		Name:       instanceFileName(config, "swagger.json"),
+		OutputType: "json",
+		Content:    b,
+	}}, nil
+}
+
+func (g *Gen) renderYAMLSwagger(config *Config, swagger *spec.Swagger) ([]Artifact, error) {
+	b, err := g.json(swagger)
 	if err != nil {
-		return err
+		return nil, err
 	}
 
-	g.debug.Printf("create swagger.json at %+v", jsonFileName)
+	y, err := g.jsonToYAML(b)
+	if err != nil {
+		return nil, fmt.Errorf("cannot covert json to yaml error: %s", err)
+	}
 
-	return nil
+	return []Artifact{{
+		Name:       instanceFileName(config, "swagger.yaml"),
+		OutputType: "yaml",
+		Content:    y,
+	}}, nil
 }
 
-func (g *Gen) writeYAMLSwagger(config *Config, swagger *spec.Swagger) error {
-	var filename = "swagger.yaml"
-
+// instanceFileName prefixes filename with the instance name, unless it is the default instance.
+func instanceFileName(config *Config, filename string) string {
 	if config.InstanceName != swag.Name {
-		filename = config.InstanceName + "_" + filename
+		return config.InstanceName + "_" + filename
 	}
 
-	yamlFileName := path.Join(config.OutputDir, filename)
+	return filename
+}
 
-	b, err := g.json(swagger)
-	if err != nil {
-		return err
+// docsPackageName returns the package name of the generated docs.
+func docsPackageName(config *Config) (string, error) {
+	if config.PackageName != "" {
+		return config.PackageName, nil
 	}
 
-	y, err := g.jsonToYAML(b)
+	absOutputDir, err := filepath.Abs(config.OutputDir)
 	if err != nil {
-		return fmt.Errorf("cannot covert json to yaml error: %s", err)
+		return "", err
 	}
 
-	err = g.writeFile(y, yamlFileName)
-	if err != nil {
-		return err
-	}
+	return filepath.Base(absOutputDir), nil
+}
 
-	g.debug.Printf("create swagger.yaml at %+v", yamlFileName)
+func hasOutputType(outputTypes []string, want string) bool {
+	for _, outputType := range outputTypes {
+		if strings.ToLower(strings.TrimSpace(outputType)) == want {
+			return true
+		}
+	}
 
-	return nil
+	return false
 }
 
 func (g *Gen) writeFile(b []byte, file string) error {
@@ -360,20 +488,9 @@ func parseOverrides(r io.Reader) (map[string]string, error) {
 	return overrides, nil
 }
 
-func (g *Gen) writeGoDoc(packageName string, output io.Writer, swagger *spec.Swagger, config *Config) error {
-	generator, err := template.New("swagger_info").Funcs(template.FuncMap{
-		"printDoc": func(v string) string {
-			// Add schemes
-			v = "{\n    \"schemes\": {{ marshal .Schemes }}," + v[1:]
-			// Sanitize backticks
-			return strings.Replace(v, "`", "`+\"`\"+`", -1)
-		},
-	}).Parse(packageTemplate)
-	if err != nil {
-		return err
-	}
-
-	swaggerSpec := &spec.Swagger{
+// craftedSpec returns swagger with the fields of swag.Spec replaced by template actions.
+func craftedSpec(swagger *spec.Swagger) *spec.Swagger {
+	return &spec.Swagger{
 		VendorExtensible: swagger.VendorExtensible,
 		SwaggerProps: spec.SwaggerProps{
 			ID:       swagger.ID,
@@ -403,18 +520,56 @@ func (g *Gen) writeGoDoc(packageName string, output io.Writer, swagger *spec.Swa
 			ExternalDocs:        swagger.ExternalDocs,
 		},
 	}
+}
+
+func (g *Gen) writeGoDoc(packageName string, output io.Writer, swagger *spec.Swagger, config *Config) error {
+	text := packageTemplate
+	if config.EmbedSpec {
+		text = embedPackageTemplate
+	}
+
+	if config.DocTemplateFile != "" {
+		custom, err := os.ReadFile(config.DocTemplateFile)
+		if err != nil {
+			return fmt.Errorf("could not read docs template: %w", err)
+		}
+
+		text = string(custom)
+	}
 
-	// crafted docs.json
-	buf, err := g.jsonIndent(swaggerSpec)
+	generator, err := template.New("swagger_info").Funcs(template.FuncMap{
+		"printDoc": func(v string) string {
+			// Add schemes
+			v = "{\n    \"schemes\": {{ marshal .Schemes }}," + v[1:]
+			// Sanitize backticks
+			return strings.Replace(v, "`", "`+\"`\"+`", -1)
+		},
+	}).Parse(text)
 	if err != nil {
 		return err
 	}
 
+	jsonFile := "swagger.json"
+	if config.InstanceName != "" && config.InstanceName != swag.Name {
+		jsonFile = config.InstanceName + "_" + jsonFile
+	}
+
+	// crafted docs.json, the embedded layout reads swagger.json instead
+	var buf []byte
+
+	if !config.EmbedSpec {
+		buf, err = g.jsonIndent(craftedSpec(swagger))
+		if err != nil {
+			return err
+		}
+	}
+
 	buffer := &bytes.Buffer{}
 
 	err = generator.Execute(buffer, struct {
 		Timestamp     time.Time
 		Doc           string
+		JSONFile      string
 		Host          string
 		PackageName   string
 		BasePath      string
@@ -424,10 +579,12 @@ func (g *Gen) writeGoDoc(packageName string, output io.Writer, swagger *spec.Swa
 		InstanceName  string
 		Schemes       []string
 		GeneratedTime bool
+		Swagger       *spec.Swagger
 	}{
 		Timestamp:     time.Now(),
 		GeneratedTime: config.GeneratedTime,
 		Doc:           string(buf),
+		JSONFile:      jsonFile,
 		Host:          swagger.Host,
 		PackageName:   packageName,
 		BasePath:      swagger.BasePath,
@@ -436,6 +593,7 @@ func (g *Gen) writeGoDoc(packageName string, output io.Writer, swagger *spec.Swa
 		Description:   swagger.Info.Description,
 		Version:       swagger.Info.Version,
 		InstanceName:  config.InstanceName,
+		Swagger:       swagger,
 	})
 	if err != nil {
 		return err
@@ -472,3 +630,32 @@ func init() {
 	swag.Register(SwaggerInfo{{ if ne .InstanceName "swagger" }}{{ .InstanceName }} {{- end }}.InstanceName(), SwaggerInfo{{ if ne .InstanceName "swagger" }}{{ .InstanceName }} {{- end }})
 }
 `
+
+var embedPackageTemplate = `// Code generated by swaggo/swag{{ if .GeneratedTime }} at {{ .Timestamp }}{{ end }}. DO NOT EDIT
+package {{.PackageName}}
+
+import (
+	_ "embed"
+
+	"github.com/swaggo/swag"
+)
+
+//go:embed {{ .JSONFile }}
+var docJSON{{ if ne .InstanceName "swagger" }}{{ .InstanceName }} {{- end }} string
+
+// SwaggerInfo{{ if ne .InstanceName "swagger" }}{{ .InstanceName }} {{- end }} holds exported Swagger Info so clients can modify it
+var SwaggerInfo{{ if ne .InstanceName "swagger" }}{{ .InstanceName }} {{- end }} = &swag.Spec{
+	Version:     {{ printf "%q" .Version}},
+	Host:        {{ printf "%q" .Host}},
+	BasePath:    {{ printf "%q" .BasePath}},
+	Schemes:     []string{ {{ range $index, $schema := .Schemes}}{{if gt $index 0}},{{end}}{{printf "%q" $schema}}{{end}} },
+	Title:       {{ printf "%q" .Title}},
+	Description: {{ printf "%q" .Description}},
+	InfoInstanceName: {{ printf "%q" .InstanceName }},
+	SwaggerJSON: docJSON{{ if ne .InstanceName "swagger" }}{{ .InstanceName }} {{- end }},
+}
+
+func init() {
+	swag.Register(SwaggerInfo{{ if ne .InstanceName "swagger" }}{{ .InstanceName }} {{- end }}.InstanceName(), SwaggerInfo{{ if ne .InstanceName "swagger" }}{{ .InstanceName }} {{- end }})
+}
+`
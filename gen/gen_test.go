@@ -154,6 +154,380 @@ func TestGen_BuildInstanceName(t *testing.T) {
 	}
 }
 
+func TestGen_BuildEmbedSpec(t *testing.T) {
+	config := &Config{
+		SearchDir:          searchDir,
+		MainAPIFile:        "./main.go",
+		OutputDir:          "../testdata/simple/docs",
+		OutputTypes:        []string{"go"},
+		PropNamingStrategy: "",
+		EmbedSpec:          true,
+	}
+
+	assert.NoError(t, New().Build(config))
+
+	code, err := os.ReadFile(filepath.Join(config.OutputDir, "docs.go"))
+	require.NoError(t, err)
+	assert.Contains(t, string(code), "//go:embed swagger.json\nvar docJSON string")
+	assert.Contains(t, string(code), "SwaggerJSON:      docJSON,")
+	assert.Contains(t, string(code), "swag.Register(SwaggerInfo.InstanceName(), SwaggerInfo)")
+	assert.NotContains(t, string(code), "docTemplate")
+
+	goCMD, err := exec.LookPath("go")
+	require.NoError(t, err)
+
+	cmd := exec.Command(goCMD, "build", "./"+filepath.ToSlash(config.OutputDir))
+	cmd.Stdout = os.Stdout
+	cmd.Stderr = os.Stderr
+	assert.NoError(t, cmd.Run())
+
+	config.InstanceName = "Embedded"
+	assert.NoError(t, New().Build(config))
+
+	code, err = os.ReadFile(filepath.Join(config.OutputDir, "Embedded_docs.go"))
+	require.NoError(t, err)
+	assert.Contains(t, string(code), "//go:embed Embedded_swagger.json\nvar docJSONEmbedded string")
+
+	expectedFiles := []string{
+		filepath.Join(config.OutputDir, "docs.go"),
+		filepath.Join(config.OutputDir, "swagger.json"),
+		filepath.Join(config.OutputDir, "Embedded_docs.go"),
+		filepath.Join(config.OutputDir, "Embedded_swagger.json"),
+	}
+	for _, expectedFile := range expectedFiles {
+		_, err := os.Stat(expectedFile)
+		require.NoError(t, err)
+
+		_ = os.Remove(expectedFile)
+	}
+}
+
+func TestGen_Generate(t *testing.T) {
+	config := &Config{
+		SearchDir:          searchDir,
+		MainAPIFile:        "./main.go",
+		OutputDir:          filepath.Join(t.TempDir(), "docs"),
+		OutputTypes:        []string{"go", "json", "yaml", "contract", "unknown"},
+		PropNamingStrategy: "",
+	}
+
+	swagger, artifacts, err := New().Generate(config)
+	require.NoError(t, err)
+	assert.Equal(t, "Swagger Example API", swagger.Info.Title)
+
+	var names []string
+	for _, artifact := range artifacts {
+		names = append(names, artifact.Name)
+	}
+	assert.Equal(t, []string{
+		"docs.go",
+		"swagger.json",
+		"swagger.yaml",
+		"swagger_contract_test.go",
+		"swagger_contract_cases_test.go",
+	}, names)
+	assert.True(t, artifacts[4].KeepExisting)
+	assert.Contains(t, string(artifacts[0].Content), "package docs")
+
+	var generated spec.Swagger
+	require.NoError(t, json.Unmarshal(artifacts[1].Content, &generated))
+	assert.Len(t, generated.Paths.Paths, len(swagger.Paths.Paths))
+
+	// nothing is written
+	_, err = os.Stat(config.OutputDir)
+	assert.True(t, os.IsNotExist(err))
+
+	config.InstanceName = "Stdout"
+	config.OutputTypes = []string{"go"}
+	config.EmbedSpec = true
+
+	artifacts, err = New().Render(config, swagger, "go")
+	require.NoError(t, err)
+	assert.Len(t, artifacts, 2)
+	assert.Equal(t, "Stdout_docs.go", artifacts[0].Name)
+	assert.Equal(t, "Stdout_swagger.json", artifacts[1].Name)
+}
+
+func TestGen_WriteTo(t *testing.T) {
+	config := &Config{
+		SearchDir:          searchDir,
+		MainAPIFile:        "./main.go",
+		OutputDir:          "../testdata/simple/docs",
+		PropNamingStrategy: "",
+	}
+
+	g := New()
+
+	swagger, err := g.Parse(config)
+	require.NoError(t, err)
+
+	var buffer bytes.Buffer
+	require.NoError(t, g.WriteTo(&buffer, "YAML", config, swagger))
+	assert.True(t, strings.HasPrefix(buffer.String(), "basePath: /v2\n"))
+
+	buffer.Reset()
+	require.NoError(t, g.WriteTo(&buffer, "go", config, swagger))
+	assert.Contains(t, buffer.String(), "package docs")
+
+	assert.EqualError(t, g.WriteTo(&buffer, "html", config, swagger), "output type 'html' not supported")
+
+	g.jsonIndent = func(data interface{}) ([]byte, error) {
+		return nil, errors.New("fail")
+	}
+	assert.Error(t, g.WriteTo(&buffer, "json", config, swagger))
+}
+
+func TestGen_BuildKeepsContractCases(t *testing.T) {
+	config := &Config{
+		SearchDir:          searchDir,
+		MainAPIFile:        "./main.go",
+		OutputDir:          t.TempDir(),
+		OutputTypes:        []string{"contract"},
+		PropNamingStrategy: "",
+	}
+
+	casesFile := filepath.Join(config.OutputDir, "swagger_contract_cases_test.go")
+	require.NoError(t, os.WriteFile(casesFile, []byte("package docs_test\n"), 0o600))
+
+	require.NoError(t, New().Build(config))
+
+	cases, err := os.ReadFile(casesFile)
+	require.NoError(t, err)
+	assert.Equal(t, "package docs_test\n", string(cases))
+
+	_, err = os.Stat(filepath.Join(config.OutputDir, "swagger_contract_test.go"))
+	assert.NoError(t, err)
+}
+
+func TestGen_BuildDocTemplate(t *testing.T) {
+	templateFile := filepath.Join(t.TempDir(), "docs.tmpl")
+	require.NoError(t, os.WriteFile(templateFile, []byte(`// Copyright Example Corp.
+
+//go:build docs
+
+package {{ .PackageName }}
+
+// PathCount is the number of documented paths.
+const PathCount = {{ len .Swagger.Paths.Paths }}
+
+// Title is the API title.
+const Title = {{ printf "%q" .Title }}
+`), 0644))
+
+	config := &Config{
+		SearchDir:          searchDir,
+		MainAPIFile:        "./main.go",
+		OutputDir:          "../testdata/simple/docs",
+		OutputTypes:        []string{"go"},
+		PropNamingStrategy: "",
+		PackageName:        "apidocs",
+		DocTemplateFile:    templateFile,
+	}
+
+	assert.NoError(t, New().Build(config))
+
+	docFile := filepath.Join(config.OutputDir, "docs.go")
+	defer os.Remove(docFile)
+
+	code, err := os.ReadFile(docFile)
+	require.NoError(t, err)
+	assert.Equal(t, `// Copyright Example Corp.
+
+//go:build docs
+
+package apidocs
+
+// PathCount is the number of documented paths.
+const PathCount = 14
+
+// Title is the API title.
+const Title = "Swagger Example API"
+`, string(code))
+
+	config.DocTemplateFile = filepath.Join(t.TempDir(), "missing.tmpl")
+	assert.Error(t, New().Build(config))
+
+	config.DocTemplateFile = ""
+	assert.NoError(t, New().Build(config))
+
+	code, err = os.ReadFile(docFile)
+	require.NoError(t, err)
+	assert.Contains(t, string(code), "package apidocs\n")
+}
+
+func TestGen_BuildContractTests(t *testing.T) {
+	config := &Config{
+		SearchDir:          searchDir,
+		MainAPIFile:        "./main.go",
+		OutputDir:          "../testdata/simple/docs",
+		OutputTypes:        []string{"contract"},
+		PropNamingStrategy: "",
+	}
+	assert.NoError(t, New().Build(config))
+
+	contractFile := filepath.Join(config.OutputDir, "swagger_contract_test.go")
+	casesFile := filepath.Join(config.OutputDir, "swagger_contract_cases_test.go")
+
+	defer func() {
+		_ = os.Remove(contractFile)
+		_ = os.Remove(casesFile)
+	}()
+
+	code, err := os.ReadFile(contractFile)
+	require.NoError(t, err)
+	assert.Contains(t, string(code), "package docs_test")
+	assert.Contains(t, string(code), "func TestContract(t *testing.T)")
+	assert.Contains(t, string(code), `"/v2/testapi/get-string-by-int/1"`)
+
+	cases, err := os.ReadFile(casesFile)
+	require.NoError(t, err)
+	assert.Contains(t, string(cases), "func contractHandler(t *testing.T) http.Handler")
+
+	// hand-edited cases survive regeneration
+	edited := append(cases, []byte("\n// edited\n")...)
+	require.NoError(t, os.WriteFile(casesFile, edited, 0644))
+	assert.NoError(t, New().Build(config))
+
+	cases, err = os.ReadFile(casesFile)
+	require.NoError(t, err)
+	assert.Equal(t, string(edited), string(cases))
+}
+
+func TestGen_buildContractCases(t *testing.T) {
+	swagger := &spec.Swagger{
+		SwaggerProps: spec.SwaggerProps{
+			BasePath: "/api/",
+			Paths: &spec.Paths{
+				Paths: map[string]spec.PathItem{
+					"/pets/{id}": {
+						PathItemProps: spec.PathItemProps{
+							Post: &spec.Operation{
+								OperationProps: spec.OperationProps{
+									Parameters: []spec.Parameter{
+										*spec.PathParam("id").Typed("integer", ""),
+										*spec.QueryParam("q").Typed("string", "").WithEnum("a", "b").AsRequired(),
+										*spec.HeaderParam("X-Trace").Typed("string", "").AsOptional(),
+										*spec.BodyParam("pet", spec.RefSchema("#/definitions/Pet")),
+									},
+									Responses: &spec.Responses{
+										ResponsesProps: spec.ResponsesProps{
+											StatusCodeResponses: map[int]spec.Response{
+												400: *spec.NewResponse(),
+												201: *spec.NewResponse().WithSchema(spec.RefSchema("#/definitions/Pet")),
+											},
+										},
+									},
+								},
+							},
+						},
+					},
+				},
+			},
+			Definitions: spec.Definitions{
+				"Pet": *spec.MapProperty(nil).
+					SetProperty("name", *spec.StringProperty()).
+					SetProperty("age", *spec.Int64Property()).
+					WithRequired("name"),
+			},
+		},
+	}
+
+	cases, err := buildContractCases(swagger)
+	require.NoError(t, err)
+	require.Len(t, cases, 1)
+
+	assert.Equal(t, contractCase{
+		Name:            "POST /pets/{id}",
+		Method:          "POST",
+		Target:          "/api/pets/1?q=a",
+		Header:          [][2]string{{"Content-Type", "application/json"}},
+		Body:            `{"age":1,"name":"string"}`,
+		WantStatus:      201,
+		WantContentType: "application/json",
+		WantSchema:      `{"$ref":"#/definitions/Pet"}`,
+	}, cases[0])
+}
+
+func TestGen_buildContractCasesContentTypeHeader(t *testing.T) {
+	operation := func(params ...spec.Parameter) *spec.Operation {
+		params = append(params, *spec.HeaderParam("content-type").Typed("string", "").AsRequired())
+
+		return &spec.Operation{
+			OperationProps: spec.OperationProps{
+				Parameters: params,
+				Responses: &spec.Responses{
+					ResponsesProps: spec.ResponsesProps{
+						StatusCodeResponses: map[int]spec.Response{204: *spec.NewResponse()},
+					},
+				},
+			},
+		}
+	}
+
+	swagger := &spec.Swagger{
+		SwaggerProps: spec.SwaggerProps{
+			Paths: &spec.Paths{
+				Paths: map[string]spec.PathItem{
+					"/pets": {
+						PathItemProps: spec.PathItemProps{
+							Post: operation(*spec.BodyParam("pet", spec.StringProperty())),
+							Put:  operation(*spec.FormDataParam("name").Typed("string", "").AsRequired()),
+						},
+					},
+				},
+			},
+		},
+	}
+
+	cases, err := buildContractCases(swagger)
+	require.NoError(t, err)
+	require.Len(t, cases, 2)
+
+	assert.Equal(t, [][2]string{{"Content-Type", "application/x-www-form-urlencoded"}}, cases[0].Header)
+	assert.Equal(t, [][2]string{{"Content-Type", "application/json"}}, cases[1].Header)
+
+	buffer := &bytes.Buffer{}
+	require.NoError(t, New().writeContractTestsTo("docs_test", buffer, swagger, &Config{}))
+	assert.Equal(t, 2, strings.Count(buffer.String(), `"Content-Type":`))
+}
+
+func TestGen_buildContractCasesResponseRef(t *testing.T) {
+	swagger := &spec.Swagger{
+		SwaggerProps: spec.SwaggerProps{
+			Paths: &spec.Paths{
+				Paths: map[string]spec.PathItem{
+					"/pets": {
+						PathItemProps: spec.PathItemProps{
+							Get: &spec.Operation{
+								OperationProps: spec.OperationProps{
+									Responses: &spec.Responses{
+										ResponsesProps: spec.ResponsesProps{
+											StatusCodeResponses: map[int]spec.Response{
+												200: *spec.ResponseRef("#/responses/Pets"),
+											},
+										},
+									},
+								},
+							},
+						},
+					},
+				},
+			},
+			Responses: map[string]spec.Response{
+				"Pets": *spec.NewResponse().WithSchema(spec.ArrayProperty(spec.RefSchema("#/definitions/Pet"))),
+			},
+		},
+	}
+
+	cases, err := buildContractCases(swagger)
+	require.NoError(t, err)
+	require.Len(t, cases, 1)
+
+	assert.Equal(t, 200, cases[0].WantStatus)
+	assert.Equal(t, "application/json", cases[0].WantContentType)
+	assert.Equal(t, `{"type":"array","items":{"$ref":"#/definitions/Pet"}}`, cases[0].WantSchema)
+}
+
 func TestGen_BuildSnakeCase(t *testing.T) {
 	config := &Config{
 		SearchDir:          "../testdata/simple2",
@@ -0,0 +1,258 @@
+package gen
+
+import (
+	"encoding/json"
+	"fmt"
+	"regexp"
+	"sort"
+	"strings"
+
+	"github.com/go-openapi/spec"
+)
+
+// Transformer modifies the parsed swagger spec before it is written.
+type Transformer interface {
+	Transform(swagger *spec.Swagger) error
+}
+
+// TransformerFunc adapts a function to the Transformer interface.
+type TransformerFunc func(swagger *spec.Swagger) error
+
+// Transform calls f(swagger).
+func (f TransformerFunc) Transform(swagger *spec.Swagger) error {
+	return f(swagger)
+}
+
+// Names of the built-in transformers, see NewBuiltinTransformer.
+const (
+	PruneDefinitionsTransformer = "pruneDefinitions"
+	SortTagsTransformer         = "sortTags"
+	StripExtensionsTransformer  = "stripExtensions"
+)
+
+// NewBuiltinTransformer returns the built-in transformer named by def. A transformer taking an argument
+// is given as name:argument, e.g. "stripExtensions:^x-internal".
+func NewBuiltinTransformer(def string) (Transformer, error) {
+	name, arg := def, ""
+	if i := strings.Index(def, ":"); i >= 0 {
+		name, arg = def[:i], def[i+1:]
+	}
+
+	switch name {
+	case PruneDefinitionsTransformer:
+		return PruneDefinitions(), nil
+	case SortTagsTransformer:
+		return SortTags(), nil
+	case StripExtensionsTransformer:
+		if arg == "" {
+			return nil, fmt.Errorf("transformer %s requires a pattern, e.g. %s:^x-internal", name, name)
+		}
+
+		return StripExtensions(arg)
+	}
+
+	return nil, fmt.Errorf("unknown transformer: %s", name)
+}
+
+// PruneDefinitions removes the definitions which are not referenced from paths, parameters or responses,
+// directly or through other definitions.
+func PruneDefinitions() Transformer {
+	return TransformerFunc(func(swagger *spec.Swagger) error {
+		if len(swagger.Definitions) == 0 {
+			return nil
+		}
+
+		used := map[string]bool{}
+		pending := []string{}
+
+		collect := func(v interface{}) error {
+			refs, err := collectRefs(v)
+			if err != nil {
+				return err
+			}
+
+			for _, ref := range refs {
+				name := strings.TrimPrefix(ref, "#/definitions/")
+				if name != ref && !used[name] {
+					used[name] = true
+					pending = append(pending, name)
+				}
+			}
+
+			return nil
+		}
+
+		for _, root := range []interface{}{swagger.Paths, swagger.Parameters, swagger.Responses} {
+			if err := collect(root); err != nil {
+				return err
+			}
+		}
+
+		for len(pending) > 0 {
+			name := pending[0]
+			pending = pending[1:]
+
+			definition, ok := swagger.Definitions[name]
+			if !ok {
+				continue
+			}
+
+			if err := collect(definition); err != nil {
+				return err
+			}
+		}
+
+		for name := range swagger.Definitions {
+			if !used[name] {
+				delete(swagger.Definitions, name)
+			}
+		}
+
+		return nil
+	})
+}
+
+// collectRefs returns every $ref value found in the JSON form of v.
+func collectRefs(v interface{}) ([]string, error) {
+	data, err := json.Marshal(v)
+	if err != nil {
+		return nil, err
+	}
+
+	var doc interface{}
+
+	err = json.Unmarshal(data, &doc)
+	if err != nil {
+		return nil, err
+	}
+
+	var refs []string
+
+	var walk func(node interface{})
+	walk = func(node interface{}) {
+		switch node := node.(type) {
+		case map[string]interface{}:
+			for key, value := range node {
+				if ref, ok := value.(string); ok && key == "$ref" {
+					refs = append(refs, ref)
+
+					continue
+				}
+
+				walk(value)
+			}
+		case []interface{}:
+			for _, value := range node {
+				walk(value)
+			}
+		}
+	}
+
+	walk(doc)
+
+	return refs, nil
+}
+
+// SortTags sorts the tag list of the spec and of each operation by name.
+func SortTags() Transformer {
+	return TransformerFunc(func(swagger *spec.Swagger) error {
+		sort.SliceStable(swagger.Tags, func(i, j int) bool {
+			return swagger.Tags[i].Name < swagger.Tags[j].Name
+		})
+
+		if swagger.Paths == nil {
+			return nil
+		}
+
+		for _, item := range swagger.Paths.Paths {
+			for _, op := range []*spec.Operation{item.Get, item.Put, item.Post, item.Delete, item.Options, item.Head, item.Patch} {
+				if op != nil {
+					sort.Strings(op.Tags)
+				}
+			}
+		}
+
+		return nil
+	})
+}
+
+// namedMaps are the keys of objects whose own keys are names rather than fields, so a property or
+// path starting with x- is not mistaken for an extension.
+var namedMaps = map[string]bool{
+	"definitions":         true,
+	"parameters":          true,
+	"responses":           true,
+	"securityDefinitions": true,
+	"paths":               true,
+	"properties":          true,
+	"patternProperties":   true,
+	"headers":             true,
+	"scopes":              true,
+}
+
+// literalValues are the keys holding user data, which is left as is.
+var literalValues = map[string]bool{
+	"example":  true,
+	"examples": true,
+	"default":  true,
+	"enum":     true,
+}
+
+// StripExtensions removes the vendor extensions (x- keys) whose name matches pattern anywhere in the spec.
+func StripExtensions(pattern string) (Transformer, error) {
+	re, err := regexp.Compile(pattern)
+	if err != nil {
+		return nil, fmt.Errorf("invalid extension pattern %q: %w", pattern, err)
+	}
+
+	return TransformerFunc(func(swagger *spec.Swagger) error {
+		data, err := json.Marshal(swagger)
+		if err != nil {
+			return err
+		}
+
+		var doc interface{}
+
+		err = json.Unmarshal(data, &doc)
+		if err != nil {
+			return err
+		}
+
+		var strip func(node interface{}, named bool)
+		strip = func(node interface{}, named bool) {
+			switch node := node.(type) {
+			case map[string]interface{}:
+				for key, value := range node {
+					if !named && strings.HasPrefix(strings.ToLower(key), "x-") {
+						if re.MatchString(key) {
+							delete(node, key)
+						}
+
+						continue
+					}
+
+					if !named && literalValues[key] {
+						continue
+					}
+
+					strip(value, !named && namedMaps[key])
+				}
+			case []interface{}:
+				for _, value := range node {
+					strip(value, false)
+				}
+			}
+		}
+
+		strip(doc, false)
+
+		data, err = json.Marshal(doc)
+		if err != nil {
+			return err
+		}
+
+		*swagger = spec.Swagger{}
+
+		return json.Unmarshal(data, swagger)
+	}), nil
+}
@@ -0,0 +1,161 @@
+package gen
+
+import (
+	"encoding/json"
+	"errors"
+	"testing"
+
+	"github.com/go-openapi/spec"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func readSwagger(t *testing.T, doc string) *spec.Swagger {
+	t.Helper()
+
+	var swagger spec.Swagger
+	require.NoError(t, json.Unmarshal([]byte(doc), &swagger))
+
+	return &swagger
+}
+
+func TestPruneDefinitions(t *testing.T) {
+	swagger := readSwagger(t, `{
+		"paths": {
+			"/pets": {
+				"get": {
+					"responses": {
+						"200": {"description": "OK", "schema": {"type": "array", "items": {"$ref": "#/definitions/Pet"}}}
+					}
+				}
+			}
+		},
+		"responses": {
+			"Error": {"description": "Error", "schema": {"$ref": "#/definitions/Error"}}
+		},
+		"definitions": {
+			"Pet": {"type": "object", "properties": {"owner": {"$ref": "#/definitions/Owner"}}},
+			"Owner": {"type": "object", "properties": {"pets": {"type": "array", "items": {"$ref": "#/definitions/Pet"}}}},
+			"Error": {"type": "object"},
+			"Unused": {"type": "object", "properties": {"pet": {"$ref": "#/definitions/Pet"}}}
+		}
+	}`)
+
+	require.NoError(t, PruneDefinitions().Transform(swagger))
+
+	names := make([]string, 0, len(swagger.Definitions))
+	for name := range swagger.Definitions {
+		names = append(names, name)
+	}
+
+	assert.ElementsMatch(t, []string{"Pet", "Owner", "Error"}, names)
+}
+
+func TestSortTags(t *testing.T) {
+	swagger := readSwagger(t, `{
+		"tags": [{"name": "pets"}, {"name": "accounts"}, {"name": "orders"}],
+		"paths": {"/pets": {"get": {"tags": ["pets", "accounts"]}}}
+	}`)
+
+	require.NoError(t, SortTags().Transform(swagger))
+
+	assert.Equal(t, "accounts", swagger.Tags[0].Name)
+	assert.Equal(t, "orders", swagger.Tags[1].Name)
+	assert.Equal(t, "pets", swagger.Tags[2].Name)
+	assert.Equal(t, []string{"accounts", "pets"}, swagger.Paths.Paths["/pets"].Get.Tags)
+}
+
+func TestStripExtensions(t *testing.T) {
+	swagger := readSwagger(t, `{
+		"x-internal-owner": "team-a",
+		"x-logo": "logo.png",
+		"info": {"title": "API", "x-internal-id": 1},
+		"paths": {
+			"/x-internal-pets": {
+				"get": {
+					"x-internal-rate": 10,
+					"x-codeSamples": [{"lang": "go", "x-internal-nested": true}],
+					"parameters": [{"name": "id", "in": "query", "type": "string", "x-internal-hidden": true}],
+					"responses": {"200": {"description": "OK"}}
+				}
+			}
+		},
+		"definitions": {
+			"Pet": {
+				"type": "object",
+				"x-internal-table": "pets",
+				"example": {"x-internal-raw": 1},
+				"properties": {"x-internal-name": {"type": "string", "x-internal-column": "name"}}
+			}
+		}
+	}`)
+
+	transformer, err := StripExtensions("^x-internal-")
+	require.NoError(t, err)
+	require.NoError(t, transformer.Transform(swagger))
+
+	data, err := json.Marshal(swagger)
+	require.NoError(t, err)
+
+	var doc map[string]interface{}
+	require.NoError(t, json.Unmarshal(data, &doc))
+
+	assert.NotContains(t, doc, "x-internal-owner")
+	assert.Equal(t, "logo.png", doc["x-logo"])
+	assert.Equal(t, map[string]interface{}{"title": "API"}, doc["info"])
+
+	op := swagger.Paths.Paths["/x-internal-pets"].Get
+	require.NotNil(t, op)
+	assert.NotContains(t, op.Extensions, "x-internal-rate")
+	assert.Equal(t, []interface{}{map[string]interface{}{"lang": "go", "x-internal-nested": true}}, op.Extensions["x-codeSamples"])
+	assert.Empty(t, op.Parameters[0].Extensions)
+
+	pet := swagger.Definitions["Pet"]
+	assert.Empty(t, pet.Extensions)
+	assert.Equal(t, map[string]interface{}{"x-internal-raw": float64(1)}, pet.Example)
+	assert.Empty(t, pet.Properties["x-internal-name"].Extensions)
+
+	_, err = StripExtensions("(")
+	assert.Error(t, err)
+}
+
+func TestNewBuiltinTransformer(t *testing.T) {
+	for _, def := range []string{"pruneDefinitions", "sortTags", "stripExtensions:^x-internal"} {
+		transformer, err := NewBuiltinTransformer(def)
+		assert.NoError(t, err)
+		assert.NotNil(t, transformer)
+	}
+
+	_, err := NewBuiltinTransformer("stripExtensions")
+	assert.Error(t, err)
+
+	_, err = NewBuiltinTransformer("unknown")
+	assert.EqualError(t, err, "unknown transformer: unknown")
+}
+
+func TestGen_BuildTransformers(t *testing.T) {
+	var calls []string
+
+	config := &Config{
+		SearchDir:          searchDir,
+		MainAPIFile:        "./main.go",
+		OutputDir:          "../testdata/simple/docs",
+		OutputTypes:        []string{},
+		PropNamingStrategy: "",
+		Transformers: []Transformer{
+			TransformerFunc(func(swagger *spec.Swagger) error {
+				calls = append(calls, "first "+swagger.Info.Title)
+
+				return nil
+			}),
+			TransformerFunc(func(swagger *spec.Swagger) error {
+				calls = append(calls, "second")
+
+				return errors.New("boom")
+			}),
+		},
+	}
+
+	assert.EqualError(t, New().Build(config), "could not transform spec: boom")
+	assert.Equal(t, []string{"first Swagger Example API", "second"}, calls)
+}
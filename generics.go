@@ -346,3 +346,15 @@ func (parser *Parser) parseGenericTypeExpr(file *ast.File, typeExpr ast.Expr) (*
 
 	return PrimitiveSchema(OBJECT), nil
 }
+
+// genericTypeExpr returns the generic type of an instantiation, e.g. Pair of Pair[K, V], or expr itself.
+func genericTypeExpr(expr ast.Expr) ast.Expr {
+	switch index := expr.(type) {
+	case *ast.IndexExpr:
+		return index.X
+	case *ast.IndexListExpr:
+		return index.X
+	}
+
+	return expr
+}
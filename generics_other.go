@@ -40,4 +40,13 @@ func (parser *Parser) parseGenericTypeExpr(file *ast.File, typeExpr ast.Expr) (*
 	}
 
 	return PrimitiveSchema(OBJECT), nil
-}
\ No newline at end of file
+}
+
+// genericTypeExpr returns the generic type of an instantiation, e.g. Box of Box[T], or expr itself.
+func genericTypeExpr(expr ast.Expr) ast.Expr {
+	if index, ok := expr.(*ast.IndexExpr); ok {
+		return index.X
+	}
+
+	return expr
+}
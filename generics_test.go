@@ -368,6 +368,20 @@ func TestGetGenericTypeName(t *testing.T) {
 	assert.Error(t, err)
 }
 
+func TestReceiverTypeName(t *testing.T) {
+	assert.Equal(t, "Box", receiverTypeName(
+		&ast.StarExpr{X: &ast.IndexExpr{X: &ast.Ident{Name: "Box"}, Index: &ast.Ident{Name: "T"}}},
+	))
+
+	assert.Equal(t, "Pair", receiverTypeName(
+		&ast.IndexListExpr{X: &ast.Ident{Name: "Pair"}, Indices: []ast.Expr{&ast.Ident{Name: "K"}, &ast.Ident{Name: "V"}}},
+	))
+
+	assert.Equal(t, "Pair", receiverTypeName(
+		&ast.StarExpr{X: &ast.IndexListExpr{X: &ast.Ident{Name: "Pair"}, Indices: []ast.Expr{&ast.Ident{Name: "K"}, &ast.Ident{Name: "V"}}}},
+	))
+}
+
 func TestParseGenericTypeExpr(t *testing.T) {
 	t.Parallel()
 
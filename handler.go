@@ -0,0 +1,220 @@
+package swag
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"mime"
+	"net/http"
+	"path"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/ghodss/yaml"
+)
+
+const (
+	jsonMIME = "application/json"
+	yamlMIME = "application/yaml"
+)
+
+// DocHandler serves a registered swagger document as JSON or YAML.
+type DocHandler struct {
+	instanceName     string
+	version          string
+	cacheControl     string
+	rewriteForwarded bool
+}
+
+// NewDocHandler creates an http.Handler serving the swagger document registered with swag.Register.
+//
+// The format is chosen from the extension of the request path (.json, .yaml or .yml) and falls back to
+// the Accept header, JSON being the default.
+func NewDocHandler(options ...func(*DocHandler)) *DocHandler {
+	handler := &DocHandler{
+		instanceName: Name,
+		cacheControl: "no-cache",
+	}
+
+	for _, option := range options {
+		option(handler)
+	}
+
+	return handler
+}
+
+// SetDocInstanceName sets the name of the registered swagger document served by the handler.
+func SetDocInstanceName(name string) func(*DocHandler) {
+	return func(h *DocHandler) {
+		h.instanceName = name
+	}
+}
+
+// SetDocVersion serves the document registered with swag.RegisterVersion for the given version.
+func SetDocVersion(version string) func(*DocHandler) {
+	return func(h *DocHandler) {
+		h.version = version
+	}
+}
+
+// SetDocCacheControl sets the Cache-Control header of the served document, "no-cache" by default
+// so that clients revalidate it with its ETag.
+func SetDocCacheControl(cacheControl string) func(*DocHandler) {
+	return func(h *DocHandler) {
+		h.cacheControl = cacheControl
+	}
+}
+
+// SetDocForwardedRewrite rewrites host, schemes and basePath of the served document from the
+// X-Forwarded-Host, X-Forwarded-Proto and X-Forwarded-Prefix request headers.
+// Only enable it behind a proxy that sets these headers.
+func SetDocForwardedRewrite(rewrite bool) func(*DocHandler) {
+	return func(h *DocHandler) {
+		h.rewriteForwarded = rewrite
+	}
+}
+
+// ServeHTTP implements http.Handler.
+func (h *DocHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet && r.Method != http.MethodHead {
+		w.Header().Set("Allow", "GET, HEAD")
+		http.Error(w, http.StatusText(http.StatusMethodNotAllowed), http.StatusMethodNotAllowed)
+
+		return
+	}
+
+	doc, err := ReadDocVersion(h.instanceName, h.version)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusNotFound)
+
+		return
+	}
+
+	body := []byte(doc)
+
+	if h.rewriteForwarded {
+		body, err = rewriteForwarded(body, r.Header)
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusInternalServerError)
+
+			return
+		}
+
+		w.Header().Add("Vary", "X-Forwarded-Host, X-Forwarded-Proto, X-Forwarded-Prefix")
+	}
+
+	contentType := negotiateDocType(r)
+	if contentType == yamlMIME {
+		body, err = yaml.JSONToYAML(body)
+		if err != nil {
+			http.Error(w, fmt.Sprintf("cannot convert swagger document to yaml: %s", err), http.StatusInternalServerError)
+
+			return
+		}
+	}
+
+	sum := sha256.Sum256(body)
+
+	w.Header().Add("Vary", "Accept")
+	w.Header().Set("Content-Type", contentType+"; charset=utf-8")
+	w.Header().Set("ETag", `"`+hex.EncodeToString(sum[:16])+`"`)
+
+	if h.cacheControl != "" {
+		w.Header().Set("Cache-Control", h.cacheControl)
+	}
+
+	// ServeContent answers If-None-Match with 304 and HEAD without a body.
+	http.ServeContent(w, r, "", time.Time{}, bytes.NewReader(body))
+}
+
+func negotiateDocType(r *http.Request) string {
+	switch path.Ext(r.URL.Path) {
+	case ".json":
+		return jsonMIME
+	case ".yaml", ".yml":
+		return yamlMIME
+	}
+
+	type accepted struct {
+		mediaType string
+		quality   float64
+	}
+
+	var ranges []accepted
+
+	for _, value := range r.Header.Values("Accept") {
+		for _, part := range strings.Split(value, ",") {
+			mediaType, params, err := mime.ParseMediaType(strings.TrimSpace(part))
+			if err != nil {
+				continue
+			}
+
+			quality := 1.0
+			if q, ok := params["q"]; ok {
+				quality, _ = strconv.ParseFloat(q, 64)
+			}
+
+			ranges = append(ranges, accepted{mediaType: mediaType, quality: quality})
+		}
+	}
+
+	sort.SliceStable(ranges, func(i, j int) bool {
+		return ranges[i].quality > ranges[j].quality
+	})
+
+	for _, accept := range ranges {
+		if accept.quality <= 0 {
+			continue
+		}
+
+		switch accept.mediaType {
+		case "application/yaml", "application/x-yaml", "text/yaml", "text/x-yaml":
+			return yamlMIME
+		case jsonMIME, "application/*", "*/*":
+			return jsonMIME
+		}
+	}
+
+	return jsonMIME
+}
+
+func rewriteForwarded(doc []byte, header http.Header) ([]byte, error) {
+	host := firstForwarded(header.Get("X-Forwarded-Host"))
+	proto := firstForwarded(header.Get("X-Forwarded-Proto"))
+	prefix := firstForwarded(header.Get("X-Forwarded-Prefix"))
+
+	if host == "" && proto == "" && prefix == "" {
+		return doc, nil
+	}
+
+	var swagger map[string]interface{}
+
+	err := json.Unmarshal(doc, &swagger)
+	if err != nil {
+		return nil, fmt.Errorf("cannot parse swagger document: %w", err)
+	}
+
+	if host != "" {
+		swagger["host"] = host
+	}
+
+	if proto != "" {
+		swagger["schemes"] = []string{proto}
+	}
+
+	if prefix != "" {
+		basePath, _ := swagger["basePath"].(string)
+		swagger["basePath"] = path.Join("/", prefix, basePath)
+	}
+
+	return json.MarshalIndent(swagger, "", "    ")
+}
+
+// firstForwarded returns the value set by the proxy closest to the client when several proxies appended to a header.
+func firstForwarded(value string) string {
+	return strings.TrimSpace(strings.Split(value, ",")[0])
+}
@@ -0,0 +1,162 @@
+package swag
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const handlerDoc = `{
+    "swagger": "2.0",
+    "info": {
+        "title": "Swagger Example API",
+        "version": "1.0"
+    },
+    "host": "petstore.swagger.io",
+    "basePath": "/v2",
+    "paths": {}
+}`
+
+type staticDoc string
+
+func (d staticDoc) ReadDoc() string {
+	return string(d)
+}
+
+func TestDocHandler_Format(t *testing.T) {
+	setup()
+	Register(Name, staticDoc(handlerDoc))
+
+	handler := NewDocHandler()
+
+	tests := []struct {
+		name        string
+		target      string
+		accept      string
+		contentType string
+	}{
+		{name: "default", target: "/doc", contentType: "application/json; charset=utf-8"},
+		{name: "json extension", target: "/doc.json", accept: "application/yaml", contentType: "application/json; charset=utf-8"},
+		{name: "yaml extension", target: "/doc.yaml", contentType: "application/yaml; charset=utf-8"},
+		{name: "yml extension", target: "/doc.yml", contentType: "application/yaml; charset=utf-8"},
+		{name: "accept yaml", target: "/doc", accept: "text/html, application/x-yaml", contentType: "application/yaml; charset=utf-8"},
+		{name: "accept quality", target: "/doc", accept: "application/yaml;q=0.5, application/json", contentType: "application/json; charset=utf-8"},
+		{name: "accept any", target: "/doc", accept: "*/*", contentType: "application/json; charset=utf-8"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := httptest.NewRequest(http.MethodGet, tt.target, nil)
+			if tt.accept != "" {
+				req.Header.Set("Accept", tt.accept)
+			}
+
+			rec := httptest.NewRecorder()
+			handler.ServeHTTP(rec, req)
+
+			assert.Equal(t, http.StatusOK, rec.Code)
+			assert.Equal(t, tt.contentType, rec.Header().Get("Content-Type"))
+			assert.Equal(t, "no-cache", rec.Header().Get("Cache-Control"))
+			assert.NotEmpty(t, rec.Header().Get("ETag"))
+
+			if tt.contentType == "application/yaml; charset=utf-8" {
+				assert.Contains(t, rec.Body.String(), "swagger: \"2.0\"\n")
+			} else {
+				assert.Equal(t, handlerDoc, rec.Body.String())
+			}
+		})
+	}
+}
+
+func TestDocHandler_ETag(t *testing.T) {
+	setup()
+	Register("handler", staticDoc(handlerDoc))
+
+	handler := NewDocHandler(SetDocInstanceName("handler"), SetDocCacheControl("public, max-age=60"))
+
+	rec := httptest.NewRecorder()
+	handler.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/doc.json", nil))
+	require.Equal(t, http.StatusOK, rec.Code)
+	assert.Equal(t, "public, max-age=60", rec.Header().Get("Cache-Control"))
+
+	etag := rec.Header().Get("ETag")
+
+	req := httptest.NewRequest(http.MethodGet, "/doc.json", nil)
+	req.Header.Set("If-None-Match", etag)
+
+	rec = httptest.NewRecorder()
+	handler.ServeHTTP(rec, req)
+	assert.Equal(t, http.StatusNotModified, rec.Code)
+	assert.Empty(t, rec.Body.String())
+
+	req = httptest.NewRequest(http.MethodGet, "/doc.yaml", nil)
+	req.Header.Set("If-None-Match", etag)
+
+	rec = httptest.NewRecorder()
+	handler.ServeHTTP(rec, req)
+	assert.Equal(t, http.StatusOK, rec.Code)
+	assert.NotEqual(t, etag, rec.Header().Get("ETag"))
+}
+
+func TestDocHandler_ForwardedRewrite(t *testing.T) {
+	setup()
+	Register(Name, staticDoc(handlerDoc))
+
+	req := httptest.NewRequest(http.MethodGet, "/doc.json", nil)
+	req.Header.Set("X-Forwarded-Host", "api.example.com, internal:8080")
+	req.Header.Set("X-Forwarded-Proto", "https")
+	req.Header.Set("X-Forwarded-Prefix", "/petstore/")
+
+	rec := httptest.NewRecorder()
+	NewDocHandler().ServeHTTP(rec, req)
+	assert.Equal(t, handlerDoc, rec.Body.String())
+
+	rec = httptest.NewRecorder()
+	NewDocHandler(SetDocForwardedRewrite(true)).ServeHTTP(rec, req)
+	require.Equal(t, http.StatusOK, rec.Code)
+
+	var swagger map[string]interface{}
+	require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &swagger))
+	assert.Equal(t, "api.example.com", swagger["host"])
+	assert.Equal(t, []interface{}{"https"}, swagger["schemes"])
+	assert.Equal(t, "/petstore/v2", swagger["basePath"])
+	assert.Contains(t, rec.Header().Values("Vary"), "X-Forwarded-Host, X-Forwarded-Proto, X-Forwarded-Prefix")
+}
+
+func TestDocHandler_Version(t *testing.T) {
+	setup()
+	Register(Name, staticDoc(handlerDoc))
+	RegisterVersion(Name, "v2", staticDoc(`{"swagger":"2.0","basePath":"/v2"}`))
+
+	rec := httptest.NewRecorder()
+	NewDocHandler(SetDocVersion("v2")).ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/doc.json", nil))
+	assert.Equal(t, `{"swagger":"2.0","basePath":"/v2"}`, rec.Body.String())
+
+	rec = httptest.NewRecorder()
+	NewDocHandler(SetDocVersion("v3")).ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/doc.json", nil))
+	assert.Equal(t, http.StatusNotFound, rec.Code)
+}
+
+func TestDocHandler_Errors(t *testing.T) {
+	setup()
+
+	rec := httptest.NewRecorder()
+	NewDocHandler().ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/doc.json", nil))
+	assert.Equal(t, http.StatusNotFound, rec.Code)
+
+	Register(Name, staticDoc(handlerDoc))
+
+	rec = httptest.NewRecorder()
+	NewDocHandler().ServeHTTP(rec, httptest.NewRequest(http.MethodPost, "/doc.json", nil))
+	assert.Equal(t, http.StatusMethodNotAllowed, rec.Code)
+	assert.Equal(t, "GET, HEAD", rec.Header().Get("Allow"))
+
+	rec = httptest.NewRecorder()
+	NewDocHandler().ServeHTTP(rec, httptest.NewRequest(http.MethodHead, "/doc.json", nil))
+	assert.Equal(t, http.StatusOK, rec.Code)
+	assert.Empty(t, rec.Body.String())
+}
@@ -0,0 +1,174 @@
+// Package specvalidate holds the route matching and schema validation shared by swagtest and swagvalidate.
+package specvalidate
+
+import (
+	"net/http"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/go-openapi/spec"
+)
+
+// Route is a documented operation matched against a request path.
+type Route struct {
+	Method    string
+	Path      string
+	Operation *spec.Operation
+	PathItem  *spec.PathItem
+
+	// Params holds the path parameter values keyed by name. It is only set by Router.Find.
+	Params map[string]string
+
+	segments []string
+}
+
+// Router matches request paths against the path templates of a swagger document.
+type Router struct {
+	basePath string
+	routes   []Route
+}
+
+// NewRouter creates Router for the operations of swagger.
+func NewRouter(swagger *spec.Swagger) *Router {
+	router := &Router{basePath: strings.TrimSuffix(swagger.BasePath, "/")}
+
+	if swagger.Paths == nil {
+		return router
+	}
+
+	for path := range swagger.Paths.Paths {
+		item := swagger.Paths.Paths[path]
+
+		for method, op := range map[string]*spec.Operation{
+			http.MethodGet:     item.Get,
+			http.MethodPut:     item.Put,
+			http.MethodPost:    item.Post,
+			http.MethodDelete:  item.Delete,
+			http.MethodOptions: item.Options,
+			http.MethodHead:    item.Head,
+			http.MethodPatch:   item.Patch,
+		} {
+			if op == nil {
+				continue
+			}
+
+			router.routes = append(router.routes, Route{
+				Method:    method,
+				Path:      path,
+				Operation: op,
+				PathItem:  &item,
+				segments:  splitPath(path),
+			})
+		}
+	}
+
+	// Literal segments win over path parameters, so /users/me is matched before /users/{id}.
+	sort.Slice(router.routes, func(i, j int) bool {
+		a, b := router.routes[i], router.routes[j]
+		if len(a.segments) != len(b.segments) {
+			return len(a.segments) < len(b.segments)
+		}
+
+		for k := range a.segments {
+			aParam, bParam := isPathParam(a.segments[k]), isPathParam(b.segments[k])
+			if aParam != bParam {
+				return bParam
+			}
+		}
+
+		if a.Path != b.Path {
+			return a.Path < b.Path
+		}
+
+		return a.Method < b.Method
+	})
+
+	return router
+}
+
+// Find returns the route documented for the given method and request path.
+// The request path must include the basePath of the document.
+func (r *Router) Find(method, path string) (Route, bool) {
+	if r.basePath != "" {
+		if path != r.basePath && !strings.HasPrefix(path, r.basePath+"/") {
+			return Route{}, false
+		}
+
+		path = strings.TrimPrefix(path, r.basePath)
+	}
+
+	segments := splitPath(path)
+
+	for _, route := range r.routes {
+		if route.Method != strings.ToUpper(method) {
+			continue
+		}
+
+		params, ok := matchSegments(route.segments, segments)
+		if !ok {
+			continue
+		}
+
+		route.Params = params
+
+		return route, true
+	}
+
+	return Route{}, false
+}
+
+// ValidPrimitive reports whether a string value such as a header or query param parses as typeName.
+func ValidPrimitive(typeName, value string) bool {
+	var err error
+
+	switch typeName {
+	case "integer":
+		_, err = strconv.ParseInt(value, 10, 64)
+	case "number":
+		_, err = strconv.ParseFloat(value, 64)
+	case "boolean":
+		_, err = strconv.ParseBool(value)
+	}
+
+	return err == nil
+}
+
+func splitPath(path string) []string {
+	path = strings.Trim(path, "/")
+	if path == "" {
+		return nil
+	}
+
+	return strings.Split(path, "/")
+}
+
+func isPathParam(segment string) bool {
+	return strings.HasPrefix(segment, "{") && strings.HasSuffix(segment, "}")
+}
+
+func matchSegments(template, segments []string) (map[string]string, bool) {
+	if len(template) != len(segments) {
+		return nil, false
+	}
+
+	params := map[string]string{}
+
+	for i, segment := range template {
+		if isPathParam(segment) {
+			if segments[i] == "" {
+				return nil, false
+			}
+
+			params[segment[1:len(segment)-1]] = segments[i]
+
+			continue
+		}
+
+		if segment != segments[i] {
+			return nil, false
+		}
+	}
+
+	return params, true
+}
@@ -0,0 +1,45 @@
+package specvalidate
+
+import (
+	"net/http"
+	"testing"
+
+	"github.com/go-openapi/spec"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRouter_Find(t *testing.T) {
+	swagger := &spec.Swagger{SwaggerProps: spec.SwaggerProps{
+		BasePath: "/api/",
+		Paths: &spec.Paths{Paths: map[string]spec.PathItem{
+			"/users/{id}":             {PathItemProps: spec.PathItemProps{Get: &spec.Operation{}}},
+			"/users/me":               {PathItemProps: spec.PathItemProps{Get: &spec.Operation{}}},
+			"/users/{id}/pets/{name}": {PathItemProps: spec.PathItemProps{Delete: &spec.Operation{}}},
+		}},
+	}}
+
+	router := NewRouter(swagger)
+
+	route, ok := router.Find(http.MethodGet, "/api/users/me")
+	assert.True(t, ok)
+	assert.Equal(t, "/users/me", route.Path)
+	assert.Empty(t, route.Params)
+
+	route, ok = router.Find(http.MethodGet, "/api/users/42")
+	assert.True(t, ok)
+	assert.Equal(t, "/users/{id}", route.Path)
+	assert.Equal(t, map[string]string{"id": "42"}, route.Params)
+
+	route, ok = router.Find("delete", "/api/users/42/pets/rex")
+	assert.True(t, ok)
+	assert.Equal(t, map[string]string{"id": "42", "name": "rex"}, route.Params)
+
+	_, ok = router.Find(http.MethodGet, "/users/42")
+	assert.False(t, ok)
+
+	_, ok = router.Find(http.MethodGet, "/api/users")
+	assert.False(t, ok)
+
+	_, ok = router.Find(http.MethodPost, "/api/users/42")
+	assert.False(t, ok)
+}
@@ -0,0 +1,228 @@
+package specvalidate
+
+import (
+	"fmt"
+	"math"
+	"reflect"
+	"regexp"
+	"sort"
+	"strings"
+
+	"github.com/go-openapi/spec"
+)
+
+// SchemaValidator collects the problems found while walking a decoded JSON value along its schema.
+type SchemaValidator struct {
+	Definitions spec.Definitions
+	Problems    []string
+}
+
+func (v *SchemaValidator) errorf(path, format string, args ...interface{}) {
+	v.Problems = append(v.Problems, path+": "+fmt.Sprintf(format, args...))
+}
+
+// Validate checks value against schema, reporting problems prefixed with path, e.g. "$.name".
+func (v *SchemaValidator) Validate(schema *spec.Schema, value interface{}, path string) {
+	if schema == nil {
+		return
+	}
+
+	ref := schema.Ref.String()
+	if ref != "" {
+		name := strings.TrimPrefix(ref, "#/definitions/")
+
+		definition, ok := v.Definitions[name]
+		if !ok {
+			v.errorf(path, "unresolved reference %s", ref)
+
+			return
+		}
+
+		v.Validate(&definition, value, path)
+
+		return
+	}
+
+	for i := range schema.AllOf {
+		v.Validate(&schema.AllOf[i], value, path)
+	}
+
+	if value == nil {
+		nullable, _ := schema.Extensions.GetBool("x-nullable")
+		if !nullable && len(schema.Type) > 0 {
+			v.errorf(path, "want %s, got null", schema.Type[0])
+		}
+
+		return
+	}
+
+	if len(schema.Enum) > 0 && !InEnum(schema.Enum, value) {
+		v.errorf(path, "%v is not one of %v", value, schema.Enum)
+	}
+
+	if len(schema.Type) == 0 {
+		if len(schema.Properties) > 0 {
+			v.validateObject(schema, value, path)
+		}
+
+		return
+	}
+
+	switch schema.Type[0] {
+	case "object":
+		v.validateObject(schema, value, path)
+	case "array":
+		v.validateArray(schema, value, path)
+	case "string":
+		v.validateString(schema, value, path)
+	case "integer", "number":
+		v.validateNumber(schema, value, path)
+	case "boolean":
+		if _, ok := value.(bool); !ok {
+			v.errorf(path, "want boolean, got %s", JSONType(value))
+		}
+	}
+}
+
+func (v *SchemaValidator) validateObject(schema *spec.Schema, value interface{}, path string) {
+	object, ok := value.(map[string]interface{})
+	if !ok {
+		v.errorf(path, "want object, got %s", JSONType(value))
+
+		return
+	}
+
+	for _, name := range schema.Required {
+		if _, ok := object[name]; !ok {
+			v.errorf(path+"."+name, "required property is missing")
+		}
+	}
+
+	keys := make([]string, 0, len(object))
+	for key := range object {
+		keys = append(keys, key)
+	}
+
+	sort.Strings(keys)
+
+	for _, key := range keys {
+		property, ok := schema.Properties[key]
+		if ok {
+			v.Validate(&property, object[key], path+"."+key)
+
+			continue
+		}
+
+		if schema.AdditionalProperties != nil && schema.AdditionalProperties.Schema != nil {
+			v.Validate(schema.AdditionalProperties.Schema, object[key], path+"."+key)
+		}
+	}
+}
+
+func (v *SchemaValidator) validateArray(schema *spec.Schema, value interface{}, path string) {
+	array, ok := value.([]interface{})
+	if !ok {
+		v.errorf(path, "want array, got %s", JSONType(value))
+
+		return
+	}
+
+	if schema.Items == nil || schema.Items.Schema == nil {
+		return
+	}
+
+	for i, item := range array {
+		v.Validate(schema.Items.Schema, item, fmt.Sprintf("%s[%d]", path, i))
+	}
+}
+
+func (v *SchemaValidator) validateString(schema *spec.Schema, value interface{}, path string) {
+	str, ok := value.(string)
+	if !ok {
+		v.errorf(path, "want string, got %s", JSONType(value))
+
+		return
+	}
+
+	v.Problems = append(v.Problems, ValidateString(path, str, schema.MinLength, schema.MaxLength, schema.Pattern)...)
+}
+
+// ValidateString checks the length and pattern constraints of a string value, reporting problems prefixed with path.
+func ValidateString(path, str string, minLength, maxLength *int64, pattern string) []string {
+	var problems []string
+
+	length := int64(len([]rune(str)))
+	if minLength != nil && length < *minLength {
+		problems = append(problems, fmt.Sprintf("%s: length %d is less than %d", path, length, *minLength))
+	}
+
+	if maxLength != nil && length > *maxLength {
+		problems = append(problems, fmt.Sprintf("%s: length %d is greater than %d", path, length, *maxLength))
+	}
+
+	if pattern != "" {
+		re, err := regexp.Compile(pattern)
+		if err == nil && !re.MatchString(str) {
+			problems = append(problems, fmt.Sprintf("%s: %q does not match pattern %s", path, str, pattern))
+		}
+	}
+
+	return problems
+}
+
+func (v *SchemaValidator) validateNumber(schema *spec.Schema, value interface{}, path string) {
+	number, ok := value.(float64)
+	if !ok {
+		v.errorf(path, "want %s, got %s", schema.Type[0], JSONType(value))
+
+		return
+	}
+
+	if schema.Type[0] == "integer" && number != math.Trunc(number) {
+		v.errorf(path, "want integer, got %v", number)
+	}
+
+	if schema.Minimum != nil && number < *schema.Minimum {
+		v.errorf(path, "%v is less than %v", number, *schema.Minimum)
+	}
+
+	if schema.Maximum != nil && number > *schema.Maximum {
+		v.errorf(path, "%v is greater than %v", number, *schema.Maximum)
+	}
+}
+
+// InEnum reports whether value is one of the enum values.
+func InEnum(enum []interface{}, value interface{}) bool {
+	for _, e := range enum {
+		if reflect.DeepEqual(e, value) {
+			return true
+		}
+
+		// enum values read from the document may be decoded with a different numeric type.
+		if fmt.Sprint(e) == fmt.Sprint(value) {
+			return true
+		}
+	}
+
+	return false
+}
+
+// JSONType returns the JSON type name of a value decoded by encoding/json.
+func JSONType(value interface{}) string {
+	switch value.(type) {
+	case nil:
+		return "null"
+	case bool:
+		return "boolean"
+	case float64:
+		return "number"
+	case string:
+		return "string"
+	case []interface{}:
+		return "array"
+	case map[string]interface{}:
+		return "object"
+	}
+
+	return fmt.Sprintf("%T", value)
+}
@@ -0,0 +1,485 @@
+package lsp
+
+import (
+	"encoding/json"
+	"fmt"
+	"go/ast"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/go-openapi/spec"
+	"github.com/swaggo/swag"
+)
+
+// attribute an operation annotation offered by completion and described by hover.
+type attribute struct {
+	name   string
+	syntax string
+}
+
+var attributes = []attribute{
+	{"@Summary", "@Summary <summary>"},
+	{"@Description", "@Description <description>"},
+	{"@Description.markdown", "@Description.markdown <file name>"},
+	{"@ID", "@ID <unique operation id>"},
+	{"@Tags", "@Tags <tag>[,<tag>...]"},
+	{"@Accept", "@Accept <mime type>[,<mime type>...]"},
+	{"@Produce", "@Produce <mime type>[,<mime type>...]"},
+	{"@Param", "@Param <name> <in> <type> <required> \"<comment>\" [attribute(value)...] | ref:<parameter>"},
+	{"@Request", "@Request <request struct>"},
+	{"@Success", "@Success <code>[,<code>...] {<data type>} <type> \"<comment>\" | <code>[,<code>...] ref:<response>"},
+	{"@Failure", "@Failure <code>[,<code>...] {<data type>} <type> \"<comment>\" | <code>[,<code>...] ref:<response>"},
+	{"@Response", "@Response <code>[,<code>...] {<data type>} <type> \"<comment>\" | <code>[,<code>...] ref:<response>"},
+	{"@Header", "@Header <code>[,<code>...] {<data type>} <name> \"<comment>\""},
+	{"@Router", "@Router <path> [<method>]"},
+	{"@Security", "@Security <security definition>[&& <security definition>...][|| <security definition>...] | none"},
+	{"@Deprecated", "@Deprecated"},
+	{"@x-codeSamples", "@x-codeSamples file"},
+}
+
+var (
+	paramLocations = []string{"query", "path", "header", "body", "formData"}
+	dataTypes      = []string{"{object}", "{array}", "{string}", "{integer}", "{number}", "{boolean}"}
+	primitiveTypes = []string{"string", "integer", "number", "boolean", "file", "object", "array"}
+	mimeTypes      = []string{
+		"json", "xml", "plain", "html", "mpfd", "x-www-form-urlencoded", "json-api",
+		"json-stream", "octet-stream", "png", "jpeg", "gif",
+	}
+	methods = []string{"[get]", "[post]", "[put]", "[patch]", "[delete]", "[head]", "[options]"}
+)
+
+var definitionRefPattern = regexp.MustCompile(`"#/definitions/([^"]+)"`)
+
+// annotation an annotation comment line of a document.
+type annotation struct {
+	// start the byte offset of the @ in the line
+	start int
+
+	// text the annotation, from the @ to the end of the line
+	text string
+}
+
+// annotationAt returns the annotation on line, if the line is a // comment starting with an @.
+func annotationAt(doc *document, line int) (annotation, bool) {
+	if line < 0 || line >= len(doc.lines) {
+		return annotation{}, false
+	}
+
+	text := doc.lines[line]
+
+	slashes := strings.Index(text, "//")
+	if slashes < 0 || strings.TrimSpace(text[:slashes]) != "" {
+		return annotation{}, false
+	}
+
+	start := slashes + 2
+	for start < len(text) && (text[start] == ' ' || text[start] == '\t' || text[start] == '/') {
+		start++
+	}
+
+	if start == len(text) || text[start] != '@' {
+		return annotation{}, false
+	}
+
+	return annotation{start: start, text: text[start:]}, true
+}
+
+// diagnostics checks the annotations of every function doc comment of doc.
+func (s *Server) diagnostics(doc *document) []diagnostic {
+	diagnostics := []diagnostic{}
+
+	if doc.file == nil || s.parser == nil {
+		return diagnostics
+	}
+
+	for _, decl := range doc.file.Decls {
+		funcDecl, ok := decl.(*ast.FuncDecl)
+		if !ok || funcDecl.Doc == nil {
+			continue
+		}
+
+		unknownAttributes := s.parser.CheckOperationAnnotations(funcDecl.Doc)
+		operation := swag.NewOperation(s.parser)
+
+		for _, comment := range funcDecl.Doc.List {
+			if err, ok := unknownAttributes[comment]; ok {
+				diagnostics = append(diagnostics, commentDiagnostic(doc, comment, severityWarning, err))
+			}
+
+			err := parseComment(operation, comment.Text, doc.file)
+			if err != nil {
+				diagnostics = append(diagnostics, commentDiagnostic(doc, comment, severityError, err))
+			}
+		}
+	}
+
+	return diagnostics
+}
+
+// commentDiagnostic reports err on the annotation of comment.
+func commentDiagnostic(doc *document, comment *ast.Comment, severity int, err error) diagnostic {
+	line := doc.fset.Position(comment.Slash).Line - 1
+	start := doc.fset.Position(comment.Slash).Column - 1
+
+	if a, ok := annotationAt(doc, line); ok {
+		start = a.start
+	}
+
+	return diagnostic{
+		Range:    lineRange(doc, line, start, len(doc.lines[line])),
+		Severity: severity,
+		Source:   "swag",
+		Message:  err.Error(),
+	}
+}
+
+// parseComment parses comment for operation, a panic on a partially typed annotation is returned as an error.
+func parseComment(operation *swag.Operation, comment string, file *ast.File) (err error) {
+	defer func() {
+		if r := recover(); r != nil {
+			err = fmt.Errorf("%v", r)
+		}
+	}()
+
+	return operation.ParseComment(comment, file)
+}
+
+func lineRange(doc *document, line, start, end int) textRange {
+	return textRange{
+		Start: position{Line: line, Character: utf16Column(doc.lines[line], start)},
+		End:   position{Line: line, Character: utf16Column(doc.lines[line], end)},
+	}
+}
+
+// field a whitespace separated field of an annotation.
+type field struct {
+	// index of the field, 0 for the attribute
+	index int
+
+	// start and end byte offsets in the line
+	start, end int
+}
+
+// fieldAt returns the field of a around the byte offset in the line, or an empty field at offset when it is
+// between fields.
+func fieldAt(a annotation, offset int) field {
+	isSpace := func(i int) bool {
+		return a.text[i-a.start] == ' ' || a.text[i-a.start] == '\t'
+	}
+
+	index, i, end := 0, a.start, a.start+len(a.text)
+
+	for i < end {
+		for i < end && isSpace(i) {
+			i++
+		}
+
+		if i == end || offset < i {
+			break
+		}
+
+		start := i
+		for i < end && !isSpace(i) {
+			i++
+		}
+
+		if offset <= i {
+			return field{index: index, start: start, end: i}
+		}
+
+		index++
+	}
+
+	return field{index: index, start: offset, end: offset}
+}
+
+func (s *Server) completion(doc *document, pos position) *completionList {
+	list := &completionList{Items: []completionItem{}}
+
+	a, ok := annotationAt(doc, pos.Line)
+	if !ok {
+		return list
+	}
+
+	offset := byteOffset(doc.lines[pos.Line], pos.Character)
+	if offset < a.start {
+		return list
+	}
+
+	tok := fieldAt(a, offset)
+	tok.end = offset
+
+	fields := strings.Fields(a.text)
+
+	var (
+		values []string
+		kind   = kindValue
+	)
+
+	if tok.index == 0 {
+		for _, attr := range attributes {
+			list.Items = append(list.Items, completionItem{
+				Label:    attr.name,
+				Kind:     kindKeyword,
+				Detail:   attr.syntax,
+				TextEdit: &textEdit{Range: lineRange(doc, pos.Line, tok.start, tok.end), NewText: attr.name},
+			})
+		}
+
+		return list
+	}
+
+	switch strings.ToLower(fields[0]) {
+	case "@param":
+		switch tok.index {
+		case 2:
+			values, kind = paramLocations, kindEnumMember
+		case 3:
+			values, kind = s.typeNames(), kindStruct
+		case 4:
+			values = []string{"true", "false"}
+		}
+	case "@success", "@failure", "@response":
+		switch tok.index {
+		case 2:
+			values, kind = dataTypes, kindTypeParam
+		case 3:
+			values, kind = s.typeNames(), kindStruct
+		}
+	case "@header":
+		if tok.index == 2 {
+			values, kind = dataTypes, kindTypeParam
+		}
+	case "@accept", "@produce":
+		if tok.index == 1 {
+			values, kind = mimeTypes, kindEnumMember
+
+			// complete the last of a comma separated list
+			if comma := strings.LastIndex(doc.lines[pos.Line][tok.start:offset], ","); comma >= 0 {
+				tok.start += comma + 1
+			}
+		}
+	case "@router":
+		if tok.index == 2 {
+			values, kind = methods, kindEnumMember
+		}
+	}
+
+	// keep the array prefix of a type
+	for strings.HasPrefix(doc.lines[pos.Line][tok.start:offset], "[]") && kind == kindStruct {
+		tok.start += 2
+	}
+
+	for _, value := range values {
+		list.Items = append(list.Items, completionItem{
+			Label:    value,
+			Kind:     kind,
+			TextEdit: &textEdit{Range: lineRange(doc, pos.Line, tok.start, tok.end), NewText: value},
+		})
+	}
+
+	return list
+}
+
+// typeNames returns the primitive types followed by the Go types known to the parser.
+func (s *Server) typeNames() []string {
+	names := append([]string{}, primitiveTypes...)
+	if s.parser != nil {
+		names = append(names, s.parser.Packages().TypeNames()...)
+	}
+
+	return names
+}
+
+func (s *Server) hover(doc *document, pos position) *hover {
+	a, ok := annotationAt(doc, pos.Line)
+	if !ok {
+		return nil
+	}
+
+	offset := byteOffset(doc.lines[pos.Line], pos.Character)
+
+	tok := fieldAt(a, offset)
+	if tok.start == tok.end {
+		return nil
+	}
+
+	tokRange := lineRange(doc, pos.Line, tok.start, tok.end)
+	fields := strings.Fields(a.text)
+
+	if tok.index == 0 {
+		for _, attr := range attributes {
+			if strings.EqualFold(attr.name, fields[0]) {
+				return &hover{
+					Contents: markupContent{Kind: "markdown", Value: "```\n" + attr.syntax + "\n```"},
+					Range:    &tokRange,
+				}
+			}
+		}
+
+		return nil
+	}
+
+	if s.parser == nil || doc.file == nil {
+		return nil
+	}
+
+	operation := swag.NewOperation(s.parser)
+	if parseComment(operation, a.text, doc.file) != nil {
+		return nil
+	}
+
+	var value interface{}
+
+	switch strings.ToLower(fields[0]) {
+	case "@param":
+		if len(operation.Parameters) == 0 {
+			return nil
+		}
+
+		value = operation.Parameters[len(operation.Parameters)-1]
+	case "@success", "@failure", "@response", "@header":
+		value = firstResponse(operation, fields[1])
+	}
+
+	if value == nil {
+		return nil
+	}
+
+	contents, err := s.schemaMarkdown(value)
+	if err != nil {
+		return nil
+	}
+
+	return &hover{
+		Contents: markupContent{Kind: "markdown", Value: contents},
+		Range:    &tokRange,
+	}
+}
+
+// firstResponse returns the response of the first code in codes.
+func firstResponse(operation *swag.Operation, codes string) *spec.Response {
+	if operation.Responses == nil {
+		return nil
+	}
+
+	code := strings.TrimSpace(strings.Split(codes, ",")[0])
+	if strings.EqualFold(code, "default") {
+		return operation.Responses.Default
+	}
+
+	status, err := strconv.Atoi(code)
+	if err != nil {
+		return nil
+	}
+
+	response, ok := operation.Responses.StatusCodeResponses[status]
+	if !ok {
+		return nil
+	}
+
+	return &response
+}
+
+// schemaMarkdown renders value and the definitions it references as JSON code blocks.
+func (s *Server) schemaMarkdown(value interface{}) (string, error) {
+	body, err := json.MarshalIndent(value, "", "  ")
+	if err != nil {
+		return "", err
+	}
+
+	var (
+		builder     strings.Builder
+		definitions = s.parser.GetSwagger().Definitions
+		seen        = make(map[string]bool)
+		pending     = []string{string(body)}
+	)
+
+	builder.WriteString("```json\n" + string(body) + "\n```\n")
+
+	for len(pending) > 0 {
+		var names []string
+
+		for _, match := range definitionRefPattern.FindAllStringSubmatch(pending[0], -1) {
+			if !seen[match[1]] {
+				seen[match[1]] = true
+				names = append(names, match[1])
+			}
+		}
+
+		pending = pending[1:]
+
+		sort.Strings(names)
+
+		for _, name := range names {
+			definition, ok := definitions[name]
+			if !ok {
+				continue
+			}
+
+			body, err := json.MarshalIndent(definition, "", "  ")
+			if err != nil {
+				return "", err
+			}
+
+			builder.WriteString("\n**" + name + "**\n```json\n" + string(body) + "\n```\n")
+			pending = append(pending, string(body))
+		}
+	}
+
+	return builder.String(), nil
+}
+
+func (s *Server) definition(doc *document, pos position) []location {
+	a, ok := annotationAt(doc, pos.Line)
+	if !ok || s.parser == nil || doc.file == nil {
+		return nil
+	}
+
+	line := doc.lines[pos.Line]
+	offset := byteOffset(line, pos.Character)
+
+	tok := fieldAt(a, offset)
+	if tok.index == 0 || tok.start == tok.end {
+		return nil
+	}
+
+	// the identifier under the cursor, e.g. model.Account in model.Response{data=[]model.Account}
+	isIdent := func(c byte) bool {
+		return c == '.' || c == '_' || c >= '0' && c <= '9' || c >= 'a' && c <= 'z' || c >= 'A' && c <= 'Z'
+	}
+
+	start, end := offset, offset
+	for start > tok.start && isIdent(line[start-1]) {
+		start--
+	}
+
+	for end < tok.end && isIdent(line[end]) {
+		end++
+	}
+
+	typeName := strings.Trim(line[start:end], ".")
+	if typeName == "" || swag.IsGolangPrimitiveType(typeName) {
+		return nil
+	}
+
+	typeSpecDef := s.parser.Packages().FindTypeSpec(typeName, doc.file)
+	if typeSpecDef == nil || typeSpecDef.TypeSpec == nil {
+		return nil
+	}
+
+	namePos, ok := s.parser.Packages().Position(typeSpecDef.File, typeSpecDef.TypeSpec.Name.Pos())
+	if !ok {
+		return nil
+	}
+
+	return []location{{
+		URI: pathToURI(namePos.Filename),
+		Range: textRange{
+			Start: position{Line: namePos.Line - 1, Character: namePos.Column - 1},
+			End:   position{Line: namePos.Line - 1, Character: namePos.Column - 1 + len(typeSpecDef.TypeSpec.Name.Name)},
+		},
+	}}
+}
@@ -0,0 +1,380 @@
+package lsp
+
+import (
+	"bufio"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"go/ast"
+	goparser "go/parser"
+	"go/token"
+	"io"
+	"log"
+	"net/textproto"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+
+	"github.com/swaggo/swag"
+)
+
+// Server implements the `lsp` command, a Language Server Protocol server for swag annotations.
+type Server struct {
+	config *Config
+	debug  swag.Debugger
+
+	// parser holds the type definitions of the workspace, annotations are checked against it
+	parser *swag.Parser
+
+	searchDirs []string
+	documents  map[string]*document
+
+	out         io.Writer
+	initialized bool
+	shutdown    bool
+}
+
+// Config specifies configuration for a language server.
+type Config struct {
+	// SearchDir the directories to load type definitions from, comma separated.
+	// The workspace folders sent by the client are used if empty.
+	SearchDir string
+
+	// Excludes dirs and files in SearchDir, comma separated
+	Excludes string
+
+	// ParseDependency whether the types of dependencies are loaded too
+	ParseDependency bool
+
+	// MainAPIFile the file of the general API info in the first search dir, main.go if empty. The reusable
+	// parameters and responses it declares can be referred to by annotations.
+	MainAPIFile string
+
+	// Debugger logs the server activity, it must not write to the standard output of a stdio server
+	Debugger swag.Debugger
+}
+
+// document an open text document.
+type document struct {
+	uri   string
+	path  string
+	lines []string
+	file  *ast.File
+	fset  *token.FileSet
+}
+
+// New creates a new language server.
+func New(config *Config) *Server {
+	debug := config.Debugger
+	if debug == nil {
+		debug = log.New(io.Discard, "", log.LstdFlags)
+	}
+
+	server := &Server{
+		config:    config,
+		debug:     debug,
+		documents: make(map[string]*document),
+	}
+
+	if config.SearchDir != "" {
+		server.searchDirs = strings.Split(config.SearchDir, ",")
+	}
+
+	return server
+}
+
+var errExitWithoutShutdown = errors.New("exit notification received before shutdown")
+
+// defaultMainAPIFile the file of the general API info, as for the init command.
+const defaultMainAPIFile = "main.go"
+
+// maxMessageSize the size limit of a message, far above the size of the source files it carries.
+const maxMessageSize = 64 << 20
+
+// Serve reads requests from in and writes responses to out until the client sends the exit notification.
+func (s *Server) Serve(in io.Reader, out io.Writer) error {
+	s.out = out
+	reader := bufio.NewReader(in)
+
+	for {
+		msg, err := readMessage(reader)
+		if err != nil {
+			if errors.Is(err, io.EOF) {
+				return nil
+			}
+
+			return err
+		}
+
+		if msg.Method == "exit" {
+			if !s.shutdown {
+				return errExitWithoutShutdown
+			}
+
+			return nil
+		}
+
+		result, rpcErr := s.handle(msg)
+		if msg.ID == nil {
+			if rpcErr != nil {
+				s.debug.Printf("warning: %s: %s", msg.Method, rpcErr.Message)
+			}
+
+			continue
+		}
+
+		err = s.write(&message{JSONRPC: "2.0", ID: msg.ID, Result: nullable(result, rpcErr), Error: rpcErr})
+		if err != nil {
+			return err
+		}
+	}
+}
+
+// nullable returns the result of a request, null unless it failed.
+func nullable(result interface{}, rpcErr *responseError) interface{} {
+	if rpcErr != nil {
+		return nil
+	}
+
+	if result == nil {
+		return json.RawMessage("null")
+	}
+
+	return result
+}
+
+func (s *Server) handle(msg *message) (interface{}, *responseError) {
+	if !s.initialized && msg.Method != "initialize" {
+		if msg.ID == nil {
+			return nil, nil
+		}
+
+		return nil, &responseError{Code: codeServerNotInitialized, Message: "server not initialized"}
+	}
+
+	switch msg.Method {
+	case "initialize":
+		var params initializeParams
+		if err := json.Unmarshal(msg.Params, &params); err != nil {
+			return nil, &responseError{Code: codeInvalidParams, Message: err.Error()}
+		}
+
+		return s.initialize(&params), nil
+	case "initialized":
+		s.loadWorkspace()
+	case "shutdown":
+		s.shutdown = true
+	case "textDocument/didOpen":
+		var params didOpenTextDocumentParams
+		if err := json.Unmarshal(msg.Params, &params); err != nil {
+			return nil, &responseError{Code: codeInvalidParams, Message: err.Error()}
+		}
+
+		s.update(params.TextDocument.URI, params.TextDocument.Text)
+	case "textDocument/didChange":
+		var params didChangeTextDocumentParams
+		if err := json.Unmarshal(msg.Params, &params); err != nil {
+			return nil, &responseError{Code: codeInvalidParams, Message: err.Error()}
+		}
+
+		// full document sync, the last change holds the whole text
+		if len(params.ContentChanges) > 0 {
+			s.update(params.TextDocument.URI, params.ContentChanges[len(params.ContentChanges)-1].Text)
+		}
+	case "textDocument/didSave":
+		// type definitions may have changed on disk
+		s.loadWorkspace()
+
+		for _, doc := range s.documents {
+			s.publishDiagnostics(doc)
+		}
+	case "textDocument/didClose":
+		var params textDocumentParams
+		if err := json.Unmarshal(msg.Params, &params); err != nil {
+			return nil, &responseError{Code: codeInvalidParams, Message: err.Error()}
+		}
+
+		delete(s.documents, params.TextDocument.URI)
+		s.notify("textDocument/publishDiagnostics", publishDiagnosticsParams{URI: params.TextDocument.URI, Diagnostics: []diagnostic{}})
+	case "textDocument/completion", "textDocument/hover", "textDocument/definition":
+		var params textDocumentPositionParams
+		if err := json.Unmarshal(msg.Params, &params); err != nil {
+			return nil, &responseError{Code: codeInvalidParams, Message: err.Error()}
+		}
+
+		doc, ok := s.documents[params.TextDocument.URI]
+		if !ok {
+			return nil, nil
+		}
+
+		switch msg.Method {
+		case "textDocument/completion":
+			return s.completion(doc, params.Position), nil
+		case "textDocument/hover":
+			return s.hover(doc, params.Position), nil
+		default:
+			return s.definition(doc, params.Position), nil
+		}
+	default:
+		if msg.ID != nil {
+			return nil, &responseError{Code: codeMethodNotFound, Message: fmt.Sprintf("method %s not supported", msg.Method)}
+		}
+	}
+
+	return nil, nil
+}
+
+func (s *Server) initialize(params *initializeParams) interface{} {
+	if len(s.searchDirs) == 0 {
+		for _, folder := range params.WorkspaceFolders {
+			s.searchDirs = append(s.searchDirs, uriToPath(folder.URI))
+		}
+	}
+
+	if len(s.searchDirs) == 0 {
+		switch {
+		case params.RootURI != "":
+			s.searchDirs = []string{uriToPath(params.RootURI)}
+		case params.RootPath != "":
+			s.searchDirs = []string{params.RootPath}
+		}
+	}
+
+	s.initialized = true
+
+	return map[string]interface{}{
+		"capabilities": map[string]interface{}{
+			"textDocumentSync": map[string]interface{}{
+				"openClose": true,
+				"change":    1, // full
+				"save":      true,
+			},
+			"completionProvider": map[string]interface{}{
+				"triggerCharacters": []string{"@", "{", " "},
+			},
+			"hoverProvider":      true,
+			"definitionProvider": true,
+		},
+		"serverInfo": map[string]interface{}{
+			"name":    "swag",
+			"version": swag.Version,
+		},
+	}
+}
+
+// loadWorkspace (re)loads the type definitions of the search dirs, and the reusable definitions of the general
+// API info.
+func (s *Server) loadWorkspace() {
+	s.parser = swag.New(
+		swag.SetDebugger(s.debug),
+		swag.SetExcludedDirsAndFiles(s.config.Excludes),
+		swag.SetParseDependency(s.config.ParseDependency),
+	)
+
+	var searchDirs []string
+
+	for _, searchDir := range s.searchDirs {
+		if _, err := os.Stat(searchDir); err == nil {
+			searchDirs = append(searchDirs, searchDir)
+		}
+	}
+
+	if len(searchDirs) > 0 {
+		mainAPIFile := s.config.MainAPIFile
+		if mainAPIFile == "" {
+			mainAPIFile = defaultMainAPIFile
+		}
+
+		mainAPIFile = filepath.Join(searchDirs[0], mainAPIFile)
+
+		if _, err := os.Stat(mainAPIFile); err == nil {
+			err = s.parser.ParseGeneralAPIInfo(mainAPIFile)
+			if err != nil {
+				s.debug.Printf("warning: failed to load general API info: %s", err)
+			}
+		}
+	}
+
+	err := s.parser.ParseTypeDefinitions(searchDirs...)
+	if err != nil {
+		s.debug.Printf("warning: failed to load type definitions: %s", err)
+	}
+}
+
+// update parses the new text of a document and publishes its diagnostics.
+func (s *Server) update(uri, text string) {
+	doc := &document{
+		uri:   uri,
+		path:  uriToPath(uri),
+		lines: splitLines(text),
+		fset:  token.NewFileSet(),
+	}
+
+	// a partial file is still useful while typing
+	doc.file, _ = goparser.ParseFile(doc.fset, doc.path, text, goparser.ParseComments)
+
+	s.documents[uri] = doc
+	s.publishDiagnostics(doc)
+}
+
+func (s *Server) publishDiagnostics(doc *document) {
+	s.notify("textDocument/publishDiagnostics", publishDiagnosticsParams{
+		URI:         doc.uri,
+		Diagnostics: s.diagnostics(doc),
+	})
+}
+
+func (s *Server) notify(method string, params interface{}) {
+	raw, err := json.Marshal(params)
+	if err != nil {
+		s.debug.Printf("warning: %s: %s", method, err)
+
+		return
+	}
+
+	err = s.write(&message{JSONRPC: "2.0", Method: method, Params: raw})
+	if err != nil {
+		s.debug.Printf("warning: %s: %s", method, err)
+	}
+}
+
+func (s *Server) write(msg *message) error {
+	body, err := json.Marshal(msg)
+	if err != nil {
+		return err
+	}
+
+	_, err = fmt.Fprintf(s.out, "Content-Length: %d\r\n\r\n%s", len(body), body)
+
+	return err
+}
+
+// readMessage reads a message framed by a Content-Length header.
+func readMessage(reader *bufio.Reader) (*message, error) {
+	header, err := textproto.NewReader(reader).ReadMIMEHeader()
+	if err != nil {
+		return nil, err
+	}
+
+	length, err := strconv.Atoi(header.Get("Content-Length"))
+	if err != nil || length < 0 || length > maxMessageSize {
+		return nil, fmt.Errorf("invalid Content-Length header: %q", header.Get("Content-Length"))
+	}
+
+	body := make([]byte, length)
+
+	_, err = io.ReadFull(reader, body)
+	if err != nil {
+		return nil, err
+	}
+
+	var msg message
+
+	err = json.Unmarshal(body, &msg)
+	if err != nil {
+		return nil, fmt.Errorf("invalid message: %w", err)
+	}
+
+	return &msg, nil
+}
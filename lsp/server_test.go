@@ -0,0 +1,283 @@
+package lsp
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const workspaceDir = "../testdata/simple"
+
+const petSource = `package api
+
+import "github.com/swaggo/swag/testdata/simple/web"
+
+// GetPet godoc
+// @Summary Get a pet
+// @Param id path int true "Pet ID"
+// @Success 200 {object} web.Pet "ok"
+// @Failure 400 {object} web.Missing "bad"
+// @Router /pets/{id} [get]
+func GetPet() {}
+`
+
+// session records the messages of a client, then runs them through a server.
+type session struct {
+	t      *testing.T
+	input  bytes.Buffer
+	nextID int
+}
+
+func (s *session) send(method string, id bool, params interface{}) {
+	msg := map[string]interface{}{"jsonrpc": "2.0", "method": method, "params": params}
+	if id {
+		s.nextID++
+		msg["id"] = s.nextID
+	}
+
+	body, err := json.Marshal(msg)
+	require.NoError(s.t, err)
+
+	_, _ = fmt.Fprintf(&s.input, "Content-Length: %d\r\n\r\n%s", len(body), body)
+}
+
+func (s *session) run(server *Server) []map[string]interface{} {
+	var output bytes.Buffer
+
+	require.NoError(s.t, server.Serve(&s.input, &output))
+
+	var messages []map[string]interface{}
+
+	reader := bufio.NewReader(&output)
+
+	for {
+		msg, err := readMessage(reader)
+		if err != nil {
+			break
+		}
+
+		var decoded map[string]interface{}
+
+		body, _ := json.Marshal(msg)
+		require.NoError(s.t, json.Unmarshal(body, &decoded))
+
+		messages = append(messages, decoded)
+	}
+
+	return messages
+}
+
+func response(t *testing.T, messages []map[string]interface{}, id int) interface{} {
+	for _, msg := range messages {
+		if msg["id"] == float64(id) {
+			return msg["result"]
+		}
+	}
+
+	t.Fatalf("no response to request %d", id)
+
+	return nil
+}
+
+func labels(result interface{}) []string {
+	var labels []string
+
+	for _, item := range result.(map[string]interface{})["items"].([]interface{}) {
+		labels = append(labels, item.(map[string]interface{})["label"].(string))
+	}
+
+	return labels
+}
+
+func TestServer(t *testing.T) {
+	docPath, err := filepath.Abs(filepath.Join(workspaceDir, "api", "lsp.go"))
+	require.NoError(t, err)
+
+	uri := pathToURI(docPath)
+	doc := map[string]interface{}{"uri": uri}
+
+	s := &session{t: t}
+	s.send("initialize", true, map[string]interface{}{})
+	s.send("initialized", false, map[string]interface{}{})
+	s.send("textDocument/didOpen", false, map[string]interface{}{
+		"textDocument": map[string]interface{}{"uri": uri, "languageId": "go", "version": 1, "text": petSource},
+	})
+	// 2: attribute names
+	s.send("textDocument/completion", true, map[string]interface{}{"textDocument": doc, "position": position{Line: 5, Character: 6}})
+	// 3: param locations
+	s.send("textDocument/completion", true, map[string]interface{}{"textDocument": doc, "position": position{Line: 6, Character: 13}})
+	// 4: types
+	s.send("textDocument/completion", true, map[string]interface{}{"textDocument": doc, "position": position{Line: 7, Character: 26}})
+	// 5: hover on the response type
+	s.send("textDocument/hover", true, map[string]interface{}{"textDocument": doc, "position": position{Line: 7, Character: 28}})
+	// 6: definition of the response type
+	s.send("textDocument/definition", true, map[string]interface{}{"textDocument": doc, "position": position{Line: 7, Character: 28}})
+	// 7: hover on the attribute
+	s.send("textDocument/hover", true, map[string]interface{}{"textDocument": doc, "position": position{Line: 9, Character: 5}})
+	// 8: unknown method
+	s.send("workspace/symbol", true, map[string]interface{}{"query": ""})
+	s.send("textDocument/didChange", false, map[string]interface{}{
+		"textDocument":   doc,
+		"contentChanges": []interface{}{map[string]interface{}{"text": strings.NewReplacer("web.Missing", "web.Pet", "@Summary", "@Sumary").Replace(petSource)}},
+	})
+	s.send("shutdown", true, nil)
+	s.send("exit", false, nil)
+
+	messages := s.run(New(&Config{SearchDir: workspaceDir}))
+
+	var diagnostics [][]interface{}
+
+	for _, msg := range messages {
+		if msg["method"] == "textDocument/publishDiagnostics" {
+			diagnostics = append(diagnostics, msg["params"].(map[string]interface{})["diagnostics"].([]interface{}))
+		}
+	}
+
+	require.Len(t, diagnostics, 2)
+	require.Len(t, diagnostics[0], 1)
+	assert.Equal(t, map[string]interface{}{
+		"range": map[string]interface{}{
+			"start": map[string]interface{}{"line": float64(8), "character": float64(3)},
+			"end":   map[string]interface{}{"line": float64(8), "character": float64(42)},
+		},
+		"severity": float64(1),
+		"source":   "swag",
+		"message":  "cannot find type definition: web.Missing",
+	}, diagnostics[0][0])
+	require.Len(t, diagnostics[1], 1)
+	assert.Equal(t, float64(2), diagnostics[1][0].(map[string]interface{})["severity"])
+	assert.Equal(t, "unknown annotation @Sumary, did you mean @Summary?", diagnostics[1][0].(map[string]interface{})["message"])
+
+	assert.Contains(t, labels(response(t, messages, 2)), "@Summary")
+	assert.Equal(t, paramLocations, labels(response(t, messages, 3)))
+
+	types := labels(response(t, messages, 4))
+	assert.Contains(t, types, "string")
+	assert.Contains(t, types, "web.Pet")
+	assert.Contains(t, types, "cross.Cross")
+
+	hover := response(t, messages, 5).(map[string]interface{})["contents"].(map[string]interface{})["value"].(string)
+	assert.Contains(t, hover, `"$ref": "#/definitions/web.Pet"`)
+	assert.Contains(t, hover, "**web.Pet**")
+	assert.Contains(t, hover, "**web.Tag**")
+
+	definition := response(t, messages, 6).([]interface{})
+	require.Len(t, definition, 1)
+
+	handlerPath, err := filepath.Abs(filepath.Join(workspaceDir, "web", "handler.go"))
+	require.NoError(t, err)
+	assert.Equal(t, map[string]interface{}{
+		"uri": pathToURI(handlerPath),
+		"range": map[string]interface{}{
+			"start": map[string]interface{}{"line": float64(10), "character": float64(5)},
+			"end":   map[string]interface{}{"line": float64(10), "character": float64(8)},
+		},
+	}, definition[0])
+
+	hover = response(t, messages, 7).(map[string]interface{})["contents"].(map[string]interface{})["value"].(string)
+	assert.Equal(t, "```\n@Router <path> [<method>]\n```", hover)
+
+	for _, msg := range messages {
+		if msg["id"] == float64(8) {
+			assert.Equal(t, float64(codeMethodNotFound), msg["error"].(map[string]interface{})["code"])
+		}
+	}
+}
+
+func TestServer_ReusableDefinitions(t *testing.T) {
+	dir := t.TempDir()
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "go.mod"), []byte("module example.com/petstore\n\ngo 1.18\n"), 0644))
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "main.go"), []byte(`package main
+
+// @title Petstore
+// @version 1.0
+// @parameters.Page page query int false "page number"
+// @responses.InternalError {string} string "internal server error"
+func main() {}
+`), 0644))
+
+	docPath := filepath.Join(dir, "api.go")
+	uri := pathToURI(docPath)
+
+	s := &session{t: t}
+	s.send("initialize", true, map[string]interface{}{})
+	s.send("initialized", false, map[string]interface{}{})
+	s.send("textDocument/didOpen", false, map[string]interface{}{
+		"textDocument": map[string]interface{}{"uri": uri, "languageId": "go", "version": 1, "text": `package main
+
+// ListPets godoc
+// @Summary List pets
+// @Param ref:Page
+// @Failure 500 ref:InternalError
+// @Failure 502 ref:BadGateway
+// @Router /pets [get]
+func ListPets() {}
+`},
+	})
+	s.send("shutdown", true, nil)
+	s.send("exit", false, nil)
+
+	messages := s.run(New(&Config{SearchDir: dir}))
+
+	var diagnostics []interface{}
+
+	for _, msg := range messages {
+		if msg["method"] == "textDocument/publishDiagnostics" {
+			diagnostics = msg["params"].(map[string]interface{})["diagnostics"].([]interface{})
+		}
+	}
+
+	require.Len(t, diagnostics, 1)
+	assert.Equal(t, "response BadGateway is not defined, declare it with @responses.BadGateway",
+		diagnostics[0].(map[string]interface{})["message"])
+}
+
+func TestServer_ExitWithoutShutdown(t *testing.T) {
+	s := &session{t: t}
+	s.send("exit", false, nil)
+
+	var output bytes.Buffer
+	assert.ErrorIs(t, New(&Config{}).Serve(&s.input, &output), errExitWithoutShutdown)
+}
+
+func TestReadMessage_InvalidContentLength(t *testing.T) {
+	for _, length := range []string{"-1", "abc", "1073741824"} {
+		reader := bufio.NewReader(strings.NewReader("Content-Length: " + length + "\r\n\r\n{}"))
+
+		_, err := readMessage(reader)
+		assert.EqualError(t, err, fmt.Sprintf("invalid Content-Length header: %q", length))
+	}
+
+	s := &session{t: t}
+	s.input.WriteString("Content-Length: -1\r\n\r\n")
+
+	var output bytes.Buffer
+	assert.EqualError(t, New(&Config{}).Serve(&s.input, &output), `invalid Content-Length header: "-1"`)
+}
+
+func TestFieldAt(t *testing.T) {
+	a := annotation{start: 3, text: "@Param  id path"}
+
+	assert.Equal(t, field{index: 0, start: 3, end: 9}, fieldAt(a, 5))
+	assert.Equal(t, field{index: 1, start: 10, end: 10}, fieldAt(a, 10))
+	assert.Equal(t, field{index: 1, start: 11, end: 13}, fieldAt(a, 13))
+	assert.Equal(t, field{index: 2, start: 14, end: 18}, fieldAt(a, 14))
+	assert.Equal(t, field{index: 3, start: 19, end: 19}, fieldAt(a, 19))
+}
+
+func TestUTF16(t *testing.T) {
+	line := "// é😀x"
+
+	assert.Equal(t, 4, utf16Column(line, 5))
+	assert.Equal(t, 6, utf16Column(line, 9))
+	assert.Equal(t, 9, byteOffset(line, 6))
+	assert.Equal(t, len(line), byteOffset(line, 100))
+}
@@ -0,0 +1,150 @@
+package swag
+
+import (
+	"go/ast"
+	"strings"
+
+	"github.com/go-openapi/spec"
+)
+
+const (
+	swaggerTypeAttr = "@swaggertype"
+	formatAttr      = "@format"
+
+	marshalJSONMethod = "MarshalJSON"
+	marshalTextMethod = "MarshalText"
+)
+
+// marshalerSchema returns the schema of a type which encodes itself, nil to parse it from its declaration.
+//
+// The doc comment of the type gives its schema like a swaggertype tag, with an optional format:
+//
+//	// Money encodes as a decimal string like "12.34".
+//	// @swaggertype string
+//	// @format decimal
+//	type Money struct {
+//		cents int64
+//	}
+//
+// Without it a type implementing encoding.TextMarshaler but not json.Marshaler is a string.
+func (parser *Parser) marshalerSchema(typeSpecDef *TypeSpecDef) (*spec.Schema, error) {
+	var swaggerType, format string
+
+	for _, comment := range typeDocComments(typeSpecDef) {
+		fields := FieldsByAnySpace(strings.TrimSpace(strings.TrimLeft(comment.Text, "/")), 2)
+		if len(fields) != 2 {
+			continue
+		}
+
+		switch strings.ToLower(fields[0]) {
+		case swaggerTypeAttr:
+			swaggerType = strings.ReplaceAll(fields[1], " ", "")
+		case formatAttr:
+			format = strings.TrimSpace(fields[1])
+		}
+	}
+
+	if swaggerType != "" {
+		schema, err := BuildCustomSchema(strings.Split(swaggerType, ","))
+		if err != nil {
+			return nil, err
+		}
+
+		schema.Format = format
+
+		return schema, nil
+	}
+
+	jsonMarshaler, textMarshaler := parser.packages.marshalerMethods(typeSpecDef)
+
+	if jsonMarshaler {
+		parser.debug.Printf("warning: %s implements json.Marshaler, declare its schema with %s if it differs from its declaration",
+			typeSpecDef.TypeName(), swaggerTypeAttr)
+
+		return nil, nil
+	}
+
+	if textMarshaler {
+		schema := PrimitiveSchema(STRING)
+		schema.Format = format
+
+		return schema, nil
+	}
+
+	return nil, nil
+}
+
+// marshalerMethods reports whether the type of typeSpecDef implements json.Marshaler and encoding.TextMarshaler,
+// with methods of its package with a value or pointer receiver.
+func (pkgDefs *PackagesDefinitions) marshalerMethods(typeSpecDef *TypeSpecDef) (jsonMarshaler, textMarshaler bool) {
+	// function scoped types have no methods
+	if typeSpecDef.ParentSpec != nil {
+		return false, false
+	}
+
+	for _, info := range pkgDefs.files {
+		if info.PackagePath != typeSpecDef.PkgPath {
+			continue
+		}
+
+		for _, decl := range info.File.Decls {
+			funcDecl, ok := decl.(*ast.FuncDecl)
+			if !ok || funcDecl.Recv == nil || len(funcDecl.Recv.List) != 1 {
+				continue
+			}
+
+			if receiverTypeName(funcDecl.Recv.List[0].Type) != typeSpecDef.Name() || !isMarshalSignature(funcDecl.Type) {
+				continue
+			}
+
+			switch funcDecl.Name.Name {
+			case marshalJSONMethod:
+				jsonMarshaler = true
+			case marshalTextMethod:
+				textMarshaler = true
+			}
+		}
+	}
+
+	return jsonMarshaler, textMarshaler
+}
+
+func receiverTypeName(expr ast.Expr) string {
+	if star, ok := expr.(*ast.StarExpr); ok {
+		expr = star.X
+	}
+
+	if ident, ok := genericTypeExpr(expr).(*ast.Ident); ok {
+		return ident.Name
+	}
+
+	return ""
+}
+
+// isMarshalSignature reports whether a method has the signature `() ([]byte, error)`.
+func isMarshalSignature(funcType *ast.FuncType) bool {
+	if funcType.Params.NumFields() != 0 || funcType.Results.NumFields() != 2 {
+		return false
+	}
+
+	results := funcType.Results.List
+
+	if len(results) == 1 {
+		// ([]byte, error) can't share a type, this is e.g. (a, b error)
+		return false
+	}
+
+	bytes, ok := results[0].Type.(*ast.ArrayType)
+	if !ok || bytes.Len != nil {
+		return false
+	}
+
+	elt, ok := bytes.Elt.(*ast.Ident)
+	if !ok || elt.Name != "byte" {
+		return false
+	}
+
+	errType, ok := results[1].Type.(*ast.Ident)
+
+	return ok && errType.Name == "error"
+}
@@ -0,0 +1,99 @@
+package swag
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParser_MarshalerSchema(t *testing.T) {
+	t.Parallel()
+
+	src := `
+package api
+
+type ID struct {
+	value int64
+}
+
+func (id ID) MarshalText() ([]byte, error) {
+	return nil, nil
+}
+
+type Status int
+
+const (
+	Active Status = iota
+	Closed
+)
+
+func (s *Status) MarshalText() (text []byte, err error) {
+	return nil, nil
+}
+
+// Money encodes as a decimal string like "12.34".
+// @swaggertype string
+// @format decimal
+type Money struct {
+	Cents int64
+}
+
+func (m Money) MarshalJSON() ([]byte, error) {
+	return nil, nil
+}
+
+type Point struct {
+	X int ` + "`json:\"x\"`" + `
+}
+
+func (p Point) MarshalJSON() ([]byte, error) {
+	return nil, nil
+}
+
+func (p Point) MarshalText() (int, error) {
+	return 0, nil
+}
+
+type Order struct {
+	ID     ID     ` + "`json:\"id\"`" + `
+	Status Status ` + "`json:\"status\"`" + `
+	Total  Money  ` + "`json:\"total\"`" + `
+	Point  Point  ` + "`json:\"point\"`" + `
+}
+
+// @Success 200 {object} Order
+// @Router /orders [get]
+func Get(){
+}
+`
+	p := New()
+	require.NoError(t, p.packages.ParseFile("api", "api/api.go", src, ParseAll))
+
+	_, err := p.packages.ParseTypes()
+	require.NoError(t, err)
+
+	require.NoError(t, p.packages.RangeFiles(p.ParseRouterAPIInfo))
+
+	b, _ := json.MarshalIndent(p.swagger.Definitions["api.Order"], "", "    ")
+	expected := `{
+    "type": "object",
+    "properties": {
+        "id": {
+            "type": "string"
+        },
+        "point": {
+            "$ref": "#/definitions/api.Point"
+        },
+        "status": {
+            "type": "string"
+        },
+        "total": {
+            "type": "string",
+            "format": "decimal"
+        }
+    }
+}`
+	assert.Equal(t, expected, string(b))
+}
@@ -7,10 +7,13 @@ import (
 	"go/ast"
 	goparser "go/parser"
 	"go/token"
+	"io/fs"
 	"net/http"
 	"os"
 	"path/filepath"
+	"reflect"
 	"regexp"
+	"sort"
 	"strconv"
 	"strings"
 
@@ -48,8 +51,42 @@ var mimeTypeAliases = map[string]string{
 	"gif":                   "image/gif",
 }
 
+// operationAttributes the attributes handled by ParseComment, with their documented spelling.
+var operationAttributes = map[string]string{
+	descriptionAttr:         "@Description",
+	descriptionMarkdownAttr: "@Description.markdown",
+	summaryAttr:             "@Summary",
+	idAttr:                  "@ID",
+	tagsAttr:                "@Tags",
+	acceptAttr:              "@Accept",
+	produceAttr:             "@Produce",
+	paramAttr:               "@Param",
+	successAttr:             "@Success",
+	failureAttr:             "@Failure",
+	responseAttr:            "@Response",
+	headerAttr:              "@Header",
+	routerAttr:              "@Router",
+	securityAttr:            "@Security",
+	deprecatedAttr:          "@Deprecated",
+	xCodeSamplesAttr:        "@x-codeSamples",
+	requestAttr:             "@Request",
+}
+
+// operationOnlyAttributes the attributes which mark a comment block as operation annotations,
+// unlike e.g. @Description or @Accept which general API info uses too.
+var operationOnlyAttributes = []string{
+	summaryAttr, idAttr, tagsAttr, paramAttr, successAttr, failureAttr, responseAttr, headerAttr, routerAttr,
+	requestAttr,
+}
+
 var mimeTypePattern = regexp.MustCompile("^[^/]+/[^/]+$")
 
+// refPrefix prefixes the name of a parameter or response declared in the general API info, e.g. `@Param ref:Page`.
+const refPrefix = "ref:"
+
+// noSecurity the `@Security` value which opts an operation out of the global security requirement.
+const noSecurity = "none"
+
 // NewOperation creates a new Operation with default properties.
 // map[int]Response.
 func NewOperation(parser *Parser, options ...func(*Operation)) *Operation {
@@ -122,7 +159,7 @@ func (operation *Operation) ParseComment(comment string, astFile *ast.File) erro
 	case descriptionAttr:
 		operation.ParseDescriptionComment(lineRemainder)
 	case descriptionMarkdownAttr:
-		commentInfo, err := getMarkdownForTag(lineRemainder, operation.parser.markdownFileDir)
+		commentInfo, err := getMarkdownForTag(operation.parser.fsys, lineRemainder, operation.parser.markdownFileDir)
 		if err != nil {
 			return err
 		}
@@ -140,6 +177,8 @@ func (operation *Operation) ParseComment(comment string, astFile *ast.File) erro
 		return operation.ParseProduceComment(lineRemainder)
 	case paramAttr:
 		return operation.ParseParamComment(lineRemainder, astFile)
+	case requestAttr:
+		return operation.ParseRequestComment(lineRemainder, astFile)
 	case successAttr, failureAttr, responseAttr:
 		return operation.ParseResponseComment(lineRemainder, astFile)
 	case headerAttr:
@@ -153,6 +192,11 @@ func (operation *Operation) ParseComment(comment string, astFile *ast.File) erro
 	case xCodeSamplesAttr:
 		return operation.ParseCodeSample(attribute, commentLine, lineRemainder)
 	default:
+		handler, ok := operation.parser.operationAnnotationHandlers[lowerAttribute]
+		if ok {
+			return handler(attribute, lineRemainder, astFile, operation)
+		}
+
 		return operation.ParseMetadata(attribute, lowerAttribute, lineRemainder)
 	}
 
@@ -162,7 +206,7 @@ func (operation *Operation) ParseComment(comment string, astFile *ast.File) erro
 // ParseCodeSample godoc.
 func (operation *Operation) ParseCodeSample(attribute, _, lineRemainder string) error {
 	if lineRemainder == "file" {
-		data, err := getCodeExampleForSummary(operation.Summary, operation.codeExampleFilesDir)
+		data, err := getCodeExampleForSummary(operation.parser.fsys, operation.Summary, operation.codeExampleFilesDir)
 		if err != nil {
 			return err
 		}
@@ -236,6 +280,10 @@ func findInSlice(arr []string, target string) bool {
 //
 // E.g. @Param   some_id     path    int     true        "Some ID".
 func (operation *Operation) ParseParamComment(commentLine string, astFile *ast.File) error {
+	if strings.HasPrefix(commentLine, refPrefix) {
+		return operation.parseParamRef(strings.TrimSpace(commentLine[len(refPrefix):]))
+	}
+
 	matches := paramPattern.FindStringSubmatch(commentLine)
 	if len(matches) != 6 {
 		return fmt.Errorf("missing required param comment parameters \"%s\"", commentLine)
@@ -276,16 +324,7 @@ func (operation *Operation) ParseParamComment(commentLine string, astFile *ast.F
 	param := createParameter(paramType, description, name, objectType, refType, required, enums, operation.parser.collectionFormatInQuery)
 
 	switch paramType {
-	case "path", "header":
-		switch objectType {
-		case ARRAY:
-			if !IsPrimitiveType(refType) {
-				return fmt.Errorf("%s is not supported array type for %s", refType, paramType)
-			}
-		case OBJECT:
-			return fmt.Errorf("%s is not supported type for %s", refType, paramType)
-		}
-	case "query", "formData":
+	case "path", "header", "query", "formData":
 		switch objectType {
 		case ARRAY:
 			if !IsPrimitiveType(refType) && !(refType == "file" && paramType == "formData") {
@@ -294,84 +333,644 @@ func (operation *Operation) ParseParamComment(commentLine string, astFile *ast.F
 		case PRIMITIVE:
 			break
 		case OBJECT:
-			schema, err := operation.parser.getTypeSchema(refType, astFile, false)
+			return operation.parseStructParams(paramType, refType, astFile)
+		}
+	case "body":
+		if objectType == PRIMITIVE {
+			param.Schema = PrimitiveSchema(refType)
+		} else {
+			schema, err := operation.parseAPIObjectSchema(commentLine, objectType, refType, astFile)
 			if err != nil {
 				return err
 			}
 
-			if len(schema.Properties) == 0 {
-				return nil
+			param.Schema = schema
+		}
+	default:
+		return fmt.Errorf("%s is not supported paramType", paramType)
+	}
+
+	err := operation.parseParamAttribute(commentLine, objectType, refType, &param)
+	if err != nil {
+		return err
+	}
+
+	operation.Operation.Parameters = append(operation.Operation.Parameters, param)
+
+	return nil
+}
+
+// paramBindingTags the struct tags naming a param in each location, as used by the gin and echo bindings.
+var paramBindingTags = map[string][]string{
+	"query":    {"query", "form"},
+	"formData": {"form"},
+	"header":   {"header"},
+	"path":     {"uri", "param"},
+}
+
+// parseStructParams expands the fields of a struct into params in paramType, named from their binding tags.
+// Nested structs are flattened, their param names are joined with the nested param separator.
+func (operation *Operation) parseStructParams(paramType, refType string, astFile *ast.File) error {
+	typeSpecDef := operation.parser.packages.FindTypeSpec(refType, astFile)
+
+	_, overridden := operation.parser.Overrides[refType]
+	if typeSpecDef != nil && !overridden {
+		_, overridden = operation.parser.Overrides[typeSpecDef.FullPath()]
+	}
+
+	// generic instances and overridden types are expanded from their schema
+	if typeSpecDef == nil || overridden || strings.Contains(refType, "[") {
+		return operation.parseSchemaParams(paramType, refType, astFile)
+	}
+
+	structType, ok := typeSpecDef.TypeSpec.Type.(*ast.StructType)
+	if !ok {
+		return operation.parseSchemaParams(paramType, refType, astFile)
+	}
+
+	params, err := operation.structFieldParams(paramType, typeSpecDef.File, structType.Fields, "", []*TypeSpecDef{typeSpecDef})
+	if err != nil {
+		return err
+	}
+
+	sort.SliceStable(params, func(i, j int) bool {
+		return params[i].Name < params[j].Name
+	})
+
+	operation.Operation.Parameters = append(operation.Operation.Parameters, params...)
+
+	return nil
+}
+
+func (operation *Operation) structFieldParams(paramType string, file *ast.File, fields *ast.FieldList, prefix string, stack []*TypeSpecDef) ([]spec.Parameter, error) {
+	var params []spec.Parameter
+
+	for _, field := range fields.List {
+		if field.Tag != nil {
+			skip, ok := reflect.StructTag(strings.ReplaceAll(field.Tag.Value, "`", "")).Lookup("swaggerignore")
+			if ok && strings.EqualFold(skip, "true") {
+				continue
 			}
+		}
 
-			items := schema.Properties.ToOrderedSchemaItems()
+		ps := operation.parser.fieldParserFactory(operation.parser, field)
+		if ps.ShouldSkip() {
+			continue
+		}
 
-			for _, item := range items {
-				name, prop := item.Name, item.Schema
-				if len(prop.Type) == 0 {
-					continue
-				}
+		fieldName, err := ps.FieldName()
+		if err != nil {
+			return nil, err
+		}
 
-				switch {
-				case prop.Type[0] == ARRAY && prop.Items.Schema != nil &&
-					len(prop.Items.Schema.Type) > 0 && IsSimplePrimitiveType(prop.Items.Schema.Type[0]):
+		// embedded struct, its fields are params of the same level
+		if fieldName == "" {
+			nested, err := operation.nestedStructParams(paramType, file, field.Type, prefix, stack)
+			if err != nil {
+				return nil, err
+			}
 
-					param = createParameter(paramType, prop.Description, name, prop.Type[0], prop.Items.Schema.Type[0], findInSlice(schema.Required, name), enums, operation.parser.collectionFormatInQuery)
+			params = append(params, nested...)
 
-				case IsSimplePrimitiveType(prop.Type[0]):
-					param = createParameter(paramType, prop.Description, name, PRIMITIVE, prop.Type[0], findInSlice(schema.Required, name), enums, operation.parser.collectionFormatInQuery)
-				default:
-					operation.parser.debug.Printf("skip field [%s] in %s is not supported type for %s", name, refType, paramType)
+			continue
+		}
+
+		name := bindingName(field, paramType, fieldName)
+		if name == "-" {
+			continue
+		}
+
+		name = prefix + name
 
-					continue
+		if paramType == "formData" {
+			objectType, ok := fileFieldType(file, field)
+			if ok {
+				required, err := ps.IsRequired()
+				if err != nil {
+					return nil, err
 				}
 
-				param.Nullable = prop.Nullable
-				param.Format = prop.Format
-				param.Default = prop.Default
-				param.Example = prop.Example
-				param.Extensions = prop.Extensions
-				param.CommonValidations.Maximum = prop.Maximum
-				param.CommonValidations.Minimum = prop.Minimum
-				param.CommonValidations.ExclusiveMaximum = prop.ExclusiveMaximum
-				param.CommonValidations.ExclusiveMinimum = prop.ExclusiveMinimum
-				param.CommonValidations.MaxLength = prop.MaxLength
-				param.CommonValidations.MinLength = prop.MinLength
-				param.CommonValidations.Pattern = prop.Pattern
-				param.CommonValidations.MaxItems = prop.MaxItems
-				param.CommonValidations.MinItems = prop.MinItems
-				param.CommonValidations.UniqueItems = prop.UniqueItems
-				param.CommonValidations.MultipleOf = prop.MultipleOf
-				param.CommonValidations.Enum = prop.Enum
-				operation.Operation.Parameters = append(operation.Operation.Parameters, param)
+				params = append(params, createParameter(paramType, fieldComment(field), name, objectType, "file", required, nil, operation.parser.collectionFormatInQuery))
+
+				continue
 			}
+		}
+
+		props, required, err := operation.parser.parseStructField(file, field)
+		if err != nil {
+			if err == ErrFuncTypeField || err == ErrSkippedField {
+				continue
+			}
+
+			return nil, err
+		}
 
-			return nil
+		prop, ok := props[fieldName]
+		if !ok {
+			continue
 		}
-	case "body":
-		if objectType == PRIMITIVE {
-			param.Schema = PrimitiveSchema(refType)
-		} else {
-			schema, err := operation.parseAPIObjectSchema(commentLine, objectType, refType, astFile)
+
+		schema := operation.resolveFieldSchema(file, field, &prop)
+
+		param, ok := operation.parameterFromSchema(paramType, name, schema, paramType == "path" || findInSlice(required, fieldName))
+		if ok {
+			params = append(params, param)
+
+			continue
+		}
+
+		if len(schema.Type) > 0 && schema.Type[0] == OBJECT {
+			nested, err := operation.nestedStructParams(paramType, file, field.Type, name+operation.parser.nestedParamSeparator, stack)
 			if err != nil {
-				return err
+				return nil, err
 			}
 
-			param.Schema = schema
+			params = append(params, nested...)
+
+			continue
 		}
-	default:
-		return fmt.Errorf("%s is not supported paramType", paramType)
+
+		operation.parser.debug.Printf("skip field [%s] is not supported type for %s", name, paramType)
 	}
 
-	err := operation.parseParamAttribute(commentLine, objectType, refType, &param)
+	return params, nil
+}
+
+// fileFieldType reports whether a form data field is an uploaded file, a *multipart.FileHeader or a field
+// tagged swaggertype:"file", and returns PRIMITIVE for a single file or ARRAY for several.
+func fileFieldType(file *ast.File, field *ast.Field) (string, bool) {
+	if field.Tag != nil {
+		typeTag := reflect.StructTag(strings.ReplaceAll(field.Tag.Value, "`", "")).Get(swaggerTypeTag)
+
+		switch strings.ReplaceAll(typeTag, " ", "") {
+		case "file", "primitive,file":
+			return PRIMITIVE, true
+		case "array,file":
+			return ARRAY, true
+		case "":
+		default:
+			return "", false
+		}
+	}
+
+	expr := field.Type
+
+	objectType := PRIMITIVE
+	if array, ok := expr.(*ast.ArrayType); ok {
+		objectType, expr = ARRAY, array.Elt
+	}
+
+	if star, ok := expr.(*ast.StarExpr); ok {
+		expr = star.X
+	}
+
+	selector, ok := expr.(*ast.SelectorExpr)
+	if !ok || selector.Sel.Name != "FileHeader" {
+		return "", false
+	}
+
+	pkg, ok := selector.X.(*ast.Ident)
+	if !ok {
+		return "", false
+	}
+
+	for _, imp := range file.Imports {
+		if strings.Trim(imp.Path.Value, `"`) != "mime/multipart" {
+			continue
+		}
+
+		if imp.Name == nil && pkg.Name == "multipart" || imp.Name != nil && imp.Name.Name == pkg.Name {
+			return objectType, true
+		}
+	}
+
+	return "", false
+}
+
+// fieldComment returns the doc comment of a field, its line comment if it has none.
+func fieldComment(field *ast.Field) string {
+	if field.Doc != nil {
+		return strings.TrimSpace(field.Doc.Text())
+	}
+
+	if field.Comment != nil {
+		return strings.TrimSpace(field.Comment.Text())
+	}
+
+	return ""
+}
+
+// nestedStructParams expands a nested or embedded struct, recursive structs are expanded only once.
+func (operation *Operation) nestedStructParams(paramType string, file *ast.File, expr ast.Expr, prefix string, stack []*TypeSpecDef) ([]spec.Parameter, error) {
+	if star, ok := expr.(*ast.StarExpr); ok {
+		expr = star.X
+	}
+
+	if structType, ok := expr.(*ast.StructType); ok {
+		return operation.structFieldParams(paramType, file, structType.Fields, prefix, stack)
+	}
+
+	typeSpecDef, structType := operation.findStructType(file, expr)
+	if typeSpecDef == nil {
+		return nil, nil
+	}
+
+	for _, parent := range stack {
+		if parent == typeSpecDef {
+			operation.parser.debug.Printf("skip recursive struct [%s] in %s params", typeSpecDef.TypeName(), paramType)
+
+			return nil, nil
+		}
+	}
+
+	return operation.structFieldParams(paramType, typeSpecDef.File, structType.Fields, prefix, append(stack, typeSpecDef))
+}
+
+// findStructType returns the definition of the named struct type of expr, nil if it isn't one.
+func (operation *Operation) findStructType(file *ast.File, expr ast.Expr) (*TypeSpecDef, *ast.StructType) {
+	typeName, err := getFieldType(file, expr, nil)
+	if err != nil {
+		return nil, nil
+	}
+
+	typeSpecDef := operation.parser.packages.FindTypeSpec(typeName, file)
+	if typeSpecDef == nil {
+		return nil, nil
+	}
+
+	structType, ok := typeSpecDef.TypeSpec.Type.(*ast.StructType)
+	if !ok {
+		return nil, nil
+	}
+
+	return typeSpecDef, structType
+}
+
+// resolveFieldSchema returns the schema a reference of a field schema points to, e.g. for enums.
+func (operation *Operation) resolveFieldSchema(file *ast.File, field *ast.Field, schema *spec.Schema) *spec.Schema {
+	if schema.Ref.String() == "" {
+		return schema
+	}
+
+	typeName, err := getFieldType(file, field.Type, nil)
+	if err != nil {
+		return schema
+	}
+
+	resolved, err := operation.parser.getTypeSchema(typeName, file, false)
+	if err != nil {
+		return schema
+	}
+
+	result := *resolved
+	if schema.Description != "" {
+		result.Description = schema.Description
+	}
+
+	return &result
+}
+
+// bindingName returns the name of a param from the binding tag of its location, fieldName if it has none.
+func bindingName(field *ast.Field, paramType, fieldName string) string {
+	if field.Tag == nil {
+		return fieldName
+	}
+
+	tag := reflect.StructTag(strings.ReplaceAll(field.Tag.Value, "`", ""))
+
+	for _, key := range paramBindingTags[paramType] {
+		value := strings.TrimSpace(strings.Split(tag.Get(key), ",")[0])
+		if value != "" {
+			return value
+		}
+	}
+
+	return fieldName
+}
+
+// parseSchemaParams expands the properties of the schema of refType into params in paramType.
+func (operation *Operation) parseSchemaParams(paramType, refType string, astFile *ast.File) error {
+	schema, err := operation.parser.getTypeSchema(refType, astFile, false)
 	if err != nil {
 		return err
 	}
 
-	operation.Operation.Parameters = append(operation.Operation.Parameters, param)
+	for _, item := range schema.Properties.ToOrderedSchemaItems() {
+		name, prop := item.Name, item.Schema
+
+		param, ok := operation.parameterFromSchema(paramType, name, &prop, paramType == "path" || findInSlice(schema.Required, name))
+		if !ok {
+			operation.parser.debug.Printf("skip field [%s] in %s is not supported type for %s", name, refType, paramType)
+
+			continue
+		}
+
+		operation.Operation.Parameters = append(operation.Operation.Parameters, param)
+	}
+
+	return nil
+}
+
+// parameterFromSchema creates a param from the schema of a struct field, ok is false unless the schema is
+// a primitive or an array of primitives.
+func (operation *Operation) parameterFromSchema(paramType, name string, prop *spec.Schema, required bool) (spec.Parameter, bool) {
+	if len(prop.Type) == 0 {
+		return spec.Parameter{}, false
+	}
+
+	var param spec.Parameter
+
+	switch {
+	case prop.Type[0] == ARRAY && prop.Items != nil && prop.Items.Schema != nil &&
+		len(prop.Items.Schema.Type) > 0 && IsSimplePrimitiveType(prop.Items.Schema.Type[0]):
+
+		param = createParameter(paramType, prop.Description, name, prop.Type[0], prop.Items.Schema.Type[0], required, nil, operation.parser.collectionFormatInQuery)
+	case IsSimplePrimitiveType(prop.Type[0]):
+		param = createParameter(paramType, prop.Description, name, PRIMITIVE, prop.Type[0], required, nil, operation.parser.collectionFormatInQuery)
+	default:
+		return spec.Parameter{}, false
+	}
+
+	param.Nullable = prop.Nullable
+	param.Format = prop.Format
+	param.Default = prop.Default
+	param.Example = prop.Example
+	param.Extensions = prop.Extensions
+	param.CommonValidations.Maximum = prop.Maximum
+	param.CommonValidations.Minimum = prop.Minimum
+	param.CommonValidations.ExclusiveMaximum = prop.ExclusiveMaximum
+	param.CommonValidations.ExclusiveMinimum = prop.ExclusiveMinimum
+	param.CommonValidations.MaxLength = prop.MaxLength
+	param.CommonValidations.MinLength = prop.MinLength
+	param.CommonValidations.Pattern = prop.Pattern
+	param.CommonValidations.MaxItems = prop.MaxItems
+	param.CommonValidations.MinItems = prop.MinItems
+	param.CommonValidations.UniqueItems = prop.UniqueItems
+	param.CommonValidations.MultipleOf = prop.MultipleOf
+	param.CommonValidations.Enum = prop.Enum
+
+	return param, true
+}
+
+// addMultipartConsumes adds multipart/form-data to the MIME types the operation consumes if it uploads files.
+func (operation *Operation) addMultipartConsumes() {
+	const multipart = "multipart/form-data"
+
+	for _, param := range operation.Parameters {
+		if param.In != "formData" || param.Type != "file" && (param.Items == nil || param.Items.Type != "file") {
+			continue
+		}
+
+		if !findInSlice(operation.Consumes, multipart) {
+			operation.Consumes = append(operation.Consumes, multipart)
+		}
+
+		return
+	}
+}
+
+// parseParamRef adds a reference to a parameter declared in the general API info.
+func (operation *Operation) parseParamRef(name string) error {
+	if _, ok := operation.parser.swagger.Parameters[name]; !ok {
+		return fmt.Errorf("parameter %s is not defined, declare it with @parameters.%s", name, name)
+	}
+
+	operation.Operation.Parameters = append(operation.Operation.Parameters, spec.Parameter{
+		Refable: spec.Refable{Ref: spec.MustCreateRef("#/parameters/" + name)},
+	})
 
 	return nil
 }
 
+// requestParamTags the struct tags of the fields of a request struct, in the order they are looked up,
+// with the location of the param they declare.
+var requestParamTags = []struct {
+	tag       string
+	paramType string
+}{
+	{tag: "path", paramType: "path"},
+	{tag: "query", paramType: "query"},
+	{tag: "header", paramType: "header"},
+	{tag: "body", paramType: "body"},
+}
+
+// ParseRequestComment derives the params of the operation from a request struct.
+// E.g. @Request CreateUserInput
+//
+//	type CreateUserInput struct {
+//		OrgID   int    `path:"org"`
+//		DryRun  bool   `query:"dry_run"`
+//		TraceID string `header:"X-Trace-Id"`
+//		Body    User
+//	}
+//
+// Fields tagged path, query or header become params in that location, the field tagged body or named Body
+// becomes the body param. Untagged embedded structs are fields of the request.
+func (operation *Operation) ParseRequestComment(commentLine string, astFile *ast.File) error {
+	refType := strings.TrimSpace(commentLine)
+	if refType == "" {
+		return fmt.Errorf("missing request type")
+	}
+
+	typeSpecDef := operation.parser.packages.FindTypeSpec(refType, astFile)
+	if typeSpecDef == nil {
+		return fmt.Errorf("cannot find type definition: %s", refType)
+	}
+
+	structType, ok := typeSpecDef.TypeSpec.Type.(*ast.StructType)
+	if !ok {
+		return fmt.Errorf("request type %s is not a struct", refType)
+	}
+
+	params, err := operation.requestParams(typeSpecDef.File, structType.Fields, []*TypeSpecDef{typeSpecDef})
+	if err != nil {
+		return fmt.Errorf("request type %s: %w", refType, err)
+	}
+
+	bodies := 0
+
+	for _, param := range params {
+		if param.In == "body" {
+			bodies++
+		}
+	}
+
+	if bodies > 1 {
+		return fmt.Errorf("request type %s has more than one body field", refType)
+	}
+
+	operation.Operation.Parameters = append(operation.Operation.Parameters, params...)
+
+	return nil
+}
+
+func (operation *Operation) requestParams(file *ast.File, fields *ast.FieldList, stack []*TypeSpecDef) ([]spec.Parameter, error) {
+	var params []spec.Parameter
+
+	for _, field := range fields.List {
+		var tag reflect.StructTag
+		if field.Tag != nil {
+			tag = reflect.StructTag(strings.ReplaceAll(field.Tag.Value, "`", ""))
+		}
+
+		if strings.EqualFold(tag.Get(swaggerIgnoreTag), "true") {
+			continue
+		}
+
+		if len(field.Names) > 0 && !ast.IsExported(field.Names[0].Name) {
+			continue
+		}
+
+		paramType, name := requestParamLocation(field, tag)
+		if name == "-" {
+			continue
+		}
+
+		if paramType == "" {
+			if len(field.Names) > 0 {
+				continue
+			}
+
+			// embedded struct, its fields are fields of the request
+			typeSpecDef, structType := operation.findStructType(file, field.Type)
+			if typeSpecDef == nil {
+				continue
+			}
+
+			for _, parent := range stack {
+				if parent == typeSpecDef {
+					return nil, fmt.Errorf("recursive embedded struct %s", typeSpecDef.TypeName())
+				}
+			}
+
+			nested, err := operation.requestParams(typeSpecDef.File, structType.Fields, append(stack, typeSpecDef))
+			if err != nil {
+				return nil, err
+			}
+
+			params = append(params, nested...)
+
+			continue
+		}
+
+		schema, required, err := operation.requestFieldSchema(file, field)
+		if err != nil {
+			return nil, err
+		}
+
+		required = required || strings.EqualFold(tag.Get("required"), "true")
+
+		if paramType == "body" {
+			_, optional := field.Type.(*ast.StarExpr)
+
+			param := createParameter(paramType, schema.Description, name, OBJECT, "", required || !optional, nil, "")
+
+			// the description belongs to the param, unwrap a reference wrapped only to describe it
+			schema.Description = ""
+			if len(schema.AllOf) == 1 && reflect.DeepEqual(*schema, spec.Schema{SchemaProps: spec.SchemaProps{AllOf: schema.AllOf}}) {
+				schema = &schema.AllOf[0]
+			}
+
+			param.Schema = schema
+
+			params = append(params, param)
+
+			continue
+		}
+
+		resolved := operation.resolveFieldSchema(file, field, schema)
+
+		param, ok := operation.parameterFromSchema(paramType, name, resolved, paramType == "path" || required)
+		if ok {
+			params = append(params, param)
+
+			continue
+		}
+
+		if len(resolved.Type) > 0 && resolved.Type[0] == OBJECT {
+			nested, err := operation.nestedStructParams(paramType, file, field.Type, name+operation.parser.nestedParamSeparator, stack)
+			if err != nil {
+				return nil, err
+			}
+
+			params = append(params, nested...)
+
+			continue
+		}
+
+		operation.parser.debug.Printf("skip field [%s] is not supported type for %s", name, paramType)
+	}
+
+	return params, nil
+}
+
+// requestParamLocation returns the location and the name of the param a field of a request struct declares,
+// an empty location if it declares none.
+func requestParamLocation(field *ast.Field, tag reflect.StructTag) (string, string) {
+	for _, item := range requestParamTags {
+		value, ok := tag.Lookup(item.tag)
+		if !ok {
+			continue
+		}
+
+		name := strings.TrimSpace(strings.Split(value, ",")[0])
+		if name == "" && len(field.Names) > 0 {
+			name = field.Names[0].Name
+		}
+
+		if name == "" {
+			name = item.tag
+		}
+
+		return item.paramType, name
+	}
+
+	if len(field.Names) > 0 && field.Names[0].Name == "Body" {
+		return "body", "body"
+	}
+
+	return "", ""
+}
+
+// requestFieldSchema returns the schema of a field of a request struct and whether it is required.
+// Unlike parseStructField it ignores the json tag, which names the field in a body rather than a param.
+func (operation *Operation) requestFieldSchema(file *ast.File, field *ast.Field) (*spec.Schema, bool, error) {
+	ps := operation.parser.fieldParserFactory(operation.parser, field)
+
+	schema, err := ps.CustomSchema()
+	if err != nil {
+		return nil, false, err
+	}
+
+	if schema == nil {
+		typeName, err := getFieldType(file, field.Type, nil)
+		if err == nil {
+			schema, err = operation.parser.getTypeSchema(typeName, file, true)
+		} else {
+			schema, err = operation.parser.parseTypeExpr(file, field.Type, false)
+		}
+
+		if err != nil {
+			return nil, false, err
+		}
+	}
+
+	// the schema of a named type is shared, complement a copy
+	result := *schema
+
+	err = ps.ComplementSchema(&result)
+	if err != nil {
+		return nil, false, err
+	}
+
+	required, err := ps.IsRequired()
+	if err != nil {
+		return nil, false, err
+	}
+
+	return &result, required, nil
+}
+
 const (
 	jsonTag             = "json"
 	bindingTag          = "binding"
@@ -695,36 +1294,55 @@ func (operation *Operation) ParseRouterComment(commentLine string) error {
 
 // ParseSecurityComment parses comment for given `security` comment string.
 func (operation *Operation) ParseSecurityComment(commentLine string) error {
+	if strings.EqualFold(strings.TrimSpace(commentLine), noSecurity) {
+		// an empty, non nil requirement list overrides the global security requirement
+		operation.Security = []map[string][]string{}
+
+		return nil
+	}
+
+	operation.Security = append(operation.Security, parseSecurity(commentLine)...)
+
+	return nil
+}
+
+// parseSecurity parses security requirements, alternatives are separated by `||`,
+// schemes required together are separated by `&&`.
+func parseSecurity(commentLine string) []map[string][]string {
 	var (
-		securityMap    = make(map[string][]string)
+		security       []map[string][]string
 		securitySource = commentLine[strings.Index(commentLine, "@Security")+1:]
 	)
 
-	for _, securityOption := range strings.Split(securitySource, "||") {
-		securityOption = strings.TrimSpace(securityOption)
+	for _, alternative := range strings.Split(securitySource, "||") {
+		securityMap := make(map[string][]string)
 
-		left, right := strings.Index(securityOption, "["), strings.Index(securityOption, "]")
+		for _, securityOption := range strings.Split(alternative, "&&") {
+			securityOption = strings.TrimSpace(securityOption)
 
-		if !(left == -1 && right == -1) {
-			scopes := securityOption[left+1 : right]
+			left, right := strings.Index(securityOption, "["), strings.Index(securityOption, "]")
 
-			var options []string
+			if !(left == -1 && right == -1) {
+				scopes := securityOption[left+1 : right]
 
-			for _, scope := range strings.Split(scopes, ",") {
-				options = append(options, strings.TrimSpace(scope))
-			}
+				var options []string
 
-			securityKey := securityOption[0:left]
-			securityMap[securityKey] = append(securityMap[securityKey], options...)
-		} else {
-			securityKey := strings.TrimSpace(securityOption)
-			securityMap[securityKey] = []string{}
+				for _, scope := range strings.Split(scopes, ",") {
+					options = append(options, strings.TrimSpace(scope))
+				}
+
+				securityKey := strings.TrimSpace(securityOption[0:left])
+				securityMap[securityKey] = append(securityMap[securityKey], options...)
+			} else {
+				securityKey := strings.TrimSpace(securityOption)
+				securityMap[securityKey] = []string{}
+			}
 		}
-	}
 
-	operation.Security = append(operation.Security, securityMap)
+		security = append(security, securityMap)
+	}
 
-	return nil
+	return security
 }
 
 // findTypeDef attempts to find the *ast.TypeSpec for a specific type given the
@@ -941,6 +1559,10 @@ func (operation *Operation) parseAPIObjectSchema(commentLine, schemaType, refTyp
 
 // ParseResponseComment parses comment for given `response` comment string.
 func (operation *Operation) ParseResponseComment(commentLine string, astFile *ast.File) error {
+	if fields := strings.Fields(commentLine); len(fields) == 2 && strings.HasPrefix(fields[1], refPrefix) {
+		return operation.parseResponseRef(fields[0], fields[1][len(refPrefix):])
+	}
+
 	matches := responsePattern.FindStringSubmatch(commentLine)
 	if len(matches) != 5 {
 		err := operation.ParseEmptyResponseComment(commentLine)
@@ -981,6 +1603,33 @@ func (operation *Operation) ParseResponseComment(commentLine string, astFile *as
 	return nil
 }
 
+// parseResponseRef adds references to a response declared in the general API info for the given codes.
+func (operation *Operation) parseResponseRef(codes, name string) error {
+	if _, ok := operation.parser.swagger.Responses[name]; !ok {
+		return fmt.Errorf("response %s is not defined, declare it with @responses.%s", name, name)
+	}
+
+	for _, codeStr := range strings.Split(codes, ",") {
+		ref := spec.ResponseRef("#/responses/" + name)
+
+		if strings.EqualFold(codeStr, defaultTag) {
+			ref.Headers = make(map[string]spec.Header)
+			operation.Responses.Default = ref
+
+			continue
+		}
+
+		code, err := strconv.Atoi(codeStr)
+		if err != nil {
+			return fmt.Errorf("can not parse response comment \"%s %s%s\"", codes, refPrefix, name)
+		}
+
+		operation.AddResponse(code, ref)
+	}
+
+	return nil
+}
+
 func newHeaderSpec(schemaType, description string) spec.Header {
 	return spec.Header{
 		SimpleSchema: spec.SimpleSchema{
@@ -1169,8 +1818,8 @@ func createParameter(paramType, description, paramName, objectType, schemaType s
 	return result
 }
 
-func getCodeExampleForSummary(summaryName string, dirPath string) ([]byte, error) {
-	dirEntries, err := os.ReadDir(dirPath)
+func getCodeExampleForSummary(fsys fs.FS, summaryName string, dirPath string) ([]byte, error) {
+	dirEntries, err := readDir(fsys, dirPath)
 	if err != nil {
 		return nil, err
 	}
@@ -1189,7 +1838,7 @@ func getCodeExampleForSummary(summaryName string, dirPath string) ([]byte, error
 		if strings.Contains(fileName, summaryName) {
 			fullPath := filepath.Join(dirPath, fileName)
 
-			commentInfo, err := os.ReadFile(fullPath)
+			commentInfo, err := readFile(fsys, fullPath)
 			if err != nil {
 				return nil, fmt.Errorf("Failed to read code example file %s error: %s ", fullPath, err)
 			}
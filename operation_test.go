@@ -2,6 +2,7 @@ package swag
 
 import (
 	"encoding/json"
+	"fmt"
 	"go/ast"
 	goparser "go/parser"
 	"go/token"
@@ -9,6 +10,7 @@ import (
 
 	"github.com/go-openapi/spec"
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 )
 
 func TestParseEmptyComment(t *testing.T) {
@@ -2112,11 +2114,48 @@ func TestParseSecurityCommentOr(t *testing.T) {
 	assert.Equal(t, operation.Security, []map[string][]string{
 		{
 			"OAuth2Implicit": {"read", "write"},
-			"Firebase":       {""},
+		},
+		{
+			"Firebase": {""},
 		},
 	})
 }
 
+func TestParseSecurityCommentAnd(t *testing.T) {
+	t.Parallel()
+
+	comment := `@Security ApiKeyAuth && OAuth2Implicit[read] || BasicAuth`
+	operation := NewOperation(nil)
+
+	err := operation.ParseComment(comment, nil)
+	assert.NoError(t, err)
+
+	assert.Equal(t, operation.Security, []map[string][]string{
+		{
+			"ApiKeyAuth":     {},
+			"OAuth2Implicit": {"read"},
+		},
+		{
+			"BasicAuth": {},
+		},
+	})
+}
+
+func TestParseSecurityCommentNone(t *testing.T) {
+	t.Parallel()
+
+	operation := NewOperation(nil)
+
+	err := operation.ParseComment(`@Security none`, nil)
+	assert.NoError(t, err)
+
+	assert.NotNil(t, operation.Security)
+	assert.Empty(t, operation.Security)
+
+	b, _ := json.Marshal(operation)
+	assert.Contains(t, string(b), `"security":[]`)
+}
+
 func TestParseMultiDescription(t *testing.T) {
 	t.Parallel()
 
@@ -2384,3 +2423,345 @@ func TestParseCodeSamples(t *testing.T) {
 		assert.Error(t, err, "no error should be thrown")
 	})
 }
+
+func TestParseCustomAnnotation(t *testing.T) {
+	t.Parallel()
+
+	var gotAttributes []string
+
+	rateLimit := func(attribute, lineRemainder string, astFile *ast.File, operation *Operation) error {
+		gotAttributes = append(gotAttributes, attribute)
+
+		if lineRemainder == "" {
+			return fmt.Errorf("%s needs a limit", attribute)
+		}
+
+		operation.Extensions["x-rate-limit"] = lineRemainder
+
+		err := operation.ParseResponseComment(`429 {string} string "Too Many Requests"`, astFile)
+		if err != nil {
+			return err
+		}
+
+		return operation.ParseResponseHeaderComment(`429 {integer} Retry-After "Seconds to wait"`, astFile)
+	}
+
+	audit := func(_, _ string, _ *ast.File, operation *Operation) error {
+		operation.Tags = append(operation.Tags, "audited")
+
+		return nil
+	}
+
+	parser := New(
+		SetOperationAnnotationHandler("@RateLimit", rateLimit),
+		SetOperationAnnotationHandler("@audit", audit),
+		SetOperationAnnotationHandler("@summary", audit),
+	)
+
+	operation := NewOperation(parser)
+	assert.NoError(t, operation.ParseComment("// @ratelimit 100/min", nil))
+	assert.NoError(t, operation.ParseComment("// @Audit", nil))
+	assert.NoError(t, operation.ParseComment("// @Summary built-in annotations win", nil))
+
+	assert.Equal(t, []string{"@ratelimit"}, gotAttributes)
+	assert.Equal(t, "100/min", operation.Extensions["x-rate-limit"])
+	assert.Equal(t, []string{"audited"}, operation.Tags)
+	assert.Equal(t, "built-in annotations win", operation.Summary)
+
+	response := operation.Responses.StatusCodeResponses[429]
+	assert.Equal(t, "Too Many Requests", response.Description)
+	assert.Equal(t, "integer", response.Headers["Retry-After"].Type)
+
+	err := operation.ParseComment("// @RateLimit", nil)
+	assert.EqualError(t, err, "@RateLimit needs a limit")
+
+	operation = NewOperation(nil)
+	assert.NoError(t, operation.ParseComment("// @RateLimit 100/min", nil))
+	assert.Empty(t, operation.Extensions)
+}
+
+func TestParseParamCommentStructBindingTags(t *testing.T) {
+	t.Parallel()
+
+	src := `
+package api
+
+type Page struct {
+	Number int ` + "`json:\"number\" form:\"page\" minimum:\"1\"`" + `
+	Size   int ` + "`json:\"size\" query:\"per_page\"`" + `
+}
+
+type Filter struct {
+	Name   string   ` + "`json:\"name\" binding:\"required\"`" + `
+	Status []string ` + "`json:\"status\"`" + `
+	Owner  *Owner   ` + "`json:\"owner\"`" + `
+	Node   Node     ` + "`json:\"node\"`" + `
+}
+
+type Owner struct {
+	ID string ` + "`json:\"id\"`" + `
+}
+
+type Node struct {
+	Value  string ` + "`json:\"value\"`" + `
+	Parent *Node  ` + "`json:\"parent\"`" + `
+}
+
+type Search struct {
+	Page
+	Filter Filter ` + "`json:\"filter\" form:\"f\"`" + `
+	Secret string ` + "`json:\"-\"`" + `
+}
+
+type Headers struct {
+	RequestID string ` + "`json:\"requestId\" header:\"X-Request-ID\"`" + `
+	Trace     string ` + "`header:\"-\"`" + `
+}
+
+type PathParams struct {
+	OrgID  int    ` + "`json:\"orgId\" uri:\"org_id\"`" + `
+	UserID string ` + "`param:\"user_id\"`" + `
+}
+
+// @Param search query Search false "search"
+// @Param headers header Headers false "headers"
+// @Param path path PathParams true "path"
+// @Router /orgs/{org_id}/users/{user_id} [get]
+func List(){
+}
+`
+	p := New(SetNestedParamSeparator("_"))
+	require.NoError(t, p.packages.ParseFile("api", "api/api.go", src, ParseAll))
+
+	_, err := p.packages.ParseTypes()
+	require.NoError(t, err)
+
+	require.NoError(t, p.packages.RangeFiles(p.ParseRouterAPIInfo))
+
+	b, _ := json.MarshalIndent(p.swagger.Paths.Paths["/orgs/{org_id}/users/{user_id}"].Get.Parameters, "", "    ")
+	expected := `[
+    {
+        "type": "string",
+        "name": "f_name",
+        "in": "query",
+        "required": true
+    },
+    {
+        "type": "string",
+        "name": "f_node_value",
+        "in": "query"
+    },
+    {
+        "type": "string",
+        "name": "f_owner_id",
+        "in": "query"
+    },
+    {
+        "type": "array",
+        "items": {
+            "type": "string"
+        },
+        "name": "f_status",
+        "in": "query"
+    },
+    {
+        "minimum": 1,
+        "type": "integer",
+        "name": "page",
+        "in": "query"
+    },
+    {
+        "type": "integer",
+        "name": "per_page",
+        "in": "query"
+    },
+    {
+        "type": "string",
+        "name": "X-Request-ID",
+        "in": "header"
+    },
+    {
+        "type": "integer",
+        "name": "org_id",
+        "in": "path",
+        "required": true
+    },
+    {
+        "type": "string",
+        "name": "user_id",
+        "in": "path",
+        "required": true
+    }
+]`
+	assert.Equal(t, expected, string(b))
+}
+
+func TestParseRequestComment(t *testing.T) {
+	t.Parallel()
+
+	src := `
+package api
+
+type User struct {
+	Name string ` + "`json:\"name\"`" + `
+}
+
+type Tracing struct {
+	TraceID string ` + "`header:\"X-Trace-Id\"`" + `
+}
+
+type Filter struct {
+	Role string ` + "`json:\"role\"`" + `
+}
+
+type CreateUserInput struct {
+	Tracing
+	// the organization
+	OrgID  int    ` + "`path:\"org\" json:\"-\"`" + `
+	DryRun bool   ` + "`query:\"dry_run\" required:\"true\"`" + `
+	Filter Filter ` + "`query:\"filter\"`" + `
+	Ignore string ` + "`query:\"-\"`" + `
+	Other  string
+	// the user to create
+	Body User
+}
+
+type TwoBodies struct {
+	Body  User
+	Other *User ` + "`body:\"other\"`" + `
+}
+
+// @Request CreateUserInput
+// @Router /orgs/{org}/users [post]
+func Create(){
+}
+`
+	p := New()
+	require.NoError(t, p.packages.ParseFile("api", "api/api.go", src, ParseAll))
+
+	_, err := p.packages.ParseTypes()
+	require.NoError(t, err)
+
+	require.NoError(t, p.packages.RangeFiles(p.ParseRouterAPIInfo))
+
+	b, _ := json.MarshalIndent(p.swagger.Paths.Paths["/orgs/{org}/users"].Post.Parameters, "", "    ")
+	expected := `[
+    {
+        "type": "string",
+        "name": "X-Trace-Id",
+        "in": "header"
+    },
+    {
+        "type": "integer",
+        "description": "the organization",
+        "name": "org",
+        "in": "path",
+        "required": true
+    },
+    {
+        "type": "boolean",
+        "name": "dry_run",
+        "in": "query",
+        "required": true
+    },
+    {
+        "type": "string",
+        "name": "filter.role",
+        "in": "query"
+    },
+    {
+        "description": "the user to create",
+        "name": "body",
+        "in": "body",
+        "required": true,
+        "schema": {
+            "$ref": "#/definitions/api.User"
+        }
+    }
+]`
+	assert.Equal(t, expected, string(b))
+
+	var astFile *ast.File
+	for file := range p.packages.files {
+		astFile = file
+	}
+
+	operation := NewOperation(p)
+	assert.EqualError(t, operation.ParseComment("@Request TwoBodies", astFile), "request type TwoBodies has more than one body field")
+	assert.EqualError(t, operation.ParseComment("@Request Missing", astFile), "cannot find type definition: Missing")
+	assert.EqualError(t, operation.ParseComment("@Request", astFile), "missing request type")
+}
+
+func TestParseParamCommentStructFiles(t *testing.T) {
+	t.Parallel()
+
+	src := `
+package api
+
+import (
+	"mime/multipart"
+	mp "mime/multipart"
+)
+
+type Upload struct {
+	// the picture
+	Picture     *multipart.FileHeader   ` + "`form:\"picture\" binding:\"required\"`" + `
+	Attachments []*mp.FileHeader         ` + "`form:\"attachments\"`" + `
+	Raw         []byte                   ` + "`form:\"raw\" swaggertype:\"file\"`" + `
+	Caption     string                   ` + "`form:\"caption\"`" + `
+}
+
+// @Param upload formData Upload true "upload"
+// @Router /pictures [post]
+func Upload(){
+}
+
+// @Accept json
+// @Param upload formData Upload true "upload"
+// @Router /pictures [put]
+func Replace(){
+}
+`
+	p := New()
+	require.NoError(t, p.packages.ParseFile("api", "api/api.go", src, ParseAll))
+
+	_, err := p.packages.ParseTypes()
+	require.NoError(t, err)
+
+	require.NoError(t, p.packages.RangeFiles(p.ParseRouterAPIInfo))
+
+	post := p.swagger.Paths.Paths["/pictures"].Post
+	assert.Equal(t, []string{"multipart/form-data"}, post.Consumes)
+
+	b, _ := json.MarshalIndent(post.Parameters, "", "    ")
+	expected := `[
+    {
+        "type": "array",
+        "items": {
+            "type": "file"
+        },
+        "name": "attachments",
+        "in": "formData"
+    },
+    {
+        "type": "string",
+        "name": "caption",
+        "in": "formData"
+    },
+    {
+        "type": "file",
+        "description": "the picture",
+        "name": "picture",
+        "in": "formData",
+        "required": true
+    },
+    {
+        "type": "file",
+        "name": "raw",
+        "in": "formData"
+    }
+]`
+	assert.Equal(t, expected, string(b))
+
+	assert.Equal(t, []string{"application/json", "multipart/form-data"}, p.swagger.Paths.Paths["/pictures"].Put.Consumes)
+}
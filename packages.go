@@ -528,6 +528,43 @@ func (pkgDefs *PackagesDefinitions) findTypeSpecFromPackagePaths(matchedPkgPaths
 	return typeDef
 }
 
+// TypeNames returns the sorted names of the package level types, as written in annotations, e.g. model.Account.
+func (pkgDefs *PackagesDefinitions) TypeNames() []string {
+	names := make(map[string]struct{})
+
+	for _, pd := range pkgDefs.packages {
+		for _, typeSpecDef := range pd.TypeDefinitions {
+			if typeSpecDef.File == nil || typeSpecDef.ParentSpec != nil {
+				continue
+			}
+
+			names[fullTypeName(typeSpecDef.File.Name.Name, typeSpecDef.Name())] = struct{}{}
+		}
+	}
+
+	sortedNames := make([]string, 0, len(names))
+	for name := range names {
+		sortedNames = append(sortedNames, name)
+	}
+
+	sort.Strings(sortedNames)
+
+	return sortedNames
+}
+
+// Position returns the position of pos in file, if file was collected by ParseFile.
+func (pkgDefs *PackagesDefinitions) Position(file *ast.File, pos token.Pos) (token.Position, bool) {
+	info, ok := pkgDefs.files[file]
+	if !ok || info.FileSet == nil {
+		return token.Position{}, false
+	}
+
+	position := info.FileSet.Position(pos)
+	position.Filename = info.Path
+
+	return position, true
+}
+
 // FindTypeSpec finds out TypeSpecDef of a type by typeName
 // @typeName the name of the target type, if it starts with a package name, find its own package path from imports on top of @file
 // @file the ast.file in which @typeName is used
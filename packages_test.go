@@ -192,6 +192,43 @@ func TestPackagesDefinitions_FindTypeSpec(t *testing.T) {
 	assert.Equal(t, nilDef, pkg.FindTypeSpec("Model", nil))
 }
 
+func TestPackagesDefinitions_TypeNamesAndPosition(t *testing.T) {
+	src := `
+package model
+
+type Account struct {
+	ID int
+}
+
+type Status string
+
+func Handler() {
+	type scoped struct{}
+}
+`
+	pd := NewPackagesDefinitions()
+	assert.NoError(t, pd.ParseFile("github.com/example/model", "model/model.go", src, ParseAll))
+
+	_, err := pd.ParseTypes()
+	assert.NoError(t, err)
+
+	assert.Equal(t, []string{"model.Account", "model.Status"}, pd.TypeNames())
+
+	typeSpecDef := pd.FindTypeSpec("model.Account", nil)
+	if assert.NotNil(t, typeSpecDef) {
+		position, ok := pd.Position(typeSpecDef.File, typeSpecDef.TypeSpec.Name.Pos())
+		assert.True(t, ok)
+		assert.Equal(t, 4, position.Line)
+		assert.Equal(t, 6, position.Column)
+
+		absPath, _ := filepath.Abs("model/model.go")
+		assert.Equal(t, absPath, position.Filename)
+	}
+
+	_, ok := pd.Position(&ast.File{}, token.NoPos)
+	assert.False(t, ok)
+}
+
 func TestPackage_rangeFiles(t *testing.T) {
 	pd := NewPackagesDefinitions()
 	pd.files = map[*ast.File]*AstFileInfo{
@@ -10,10 +10,13 @@ import (
 	"go/build"
 	goparser "go/parser"
 	"go/token"
+	"go/types"
+	"io/fs"
 	"log"
 	"net/http"
 	"os"
 	"os/exec"
+	"path"
 	"path/filepath"
 	"reflect"
 	"sort"
@@ -67,6 +70,11 @@ const (
 	extDocsURLAttr          = "@externaldocs.url"
 	xCodeSamplesAttr        = "@x-codesamples"
 	scopeAttrPrefix         = "@scope."
+	parametersAttrPrefix    = "@parameters."
+	responsesAttrPrefix     = "@responses."
+	defaultResponseAttr     = "@default.response"
+	defaultHeaderAttr       = "@default.header"
+	requestAttr             = "@request"
 )
 
 // ParseFlag determine what to parse
@@ -171,8 +179,47 @@ type Parser struct {
 
 	// tags to filter the APIs after
 	tags map[string]struct{}
+
+	// operationAnnotationHandlers handle custom operation annotations, keyed by lower case attribute
+	operationAnnotationHandlers map[string]OperationAnnotationHandler
+
+	// fsys is the file system sources, markdown and code examples are read from, the OS file system if nil
+	fsys fs.FS
+
+	// operations store every parsed operation with its source location, in parse order
+	operations []ParsedOperation
+
+	// generalAPIFile the file holding the general API info
+	generalAPIFile *ast.File
+
+	// reusableDefinitions the named parameters and responses and the default responses of the general API info,
+	// parsed once the types are known
+	reusableDefinitions []reusableDefinition
+
+	// defaultResponses the responses and response headers merged into every operation in their scope
+	defaultResponses []defaultResponse
+
+	// packageDefaults the operation defaults of the package doc comments, keyed by package path
+	packageDefaults map[string]*packageDefaults
+
+	// nestedParamSeparator joins the names of nested struct fields expanded into params
+	nestedParamSeparator string
+
+	// wellKnownTypes the schemas of types which their declarations don't describe, keyed by import path and name
+	wellKnownTypes map[string]spec.Schema
 }
 
+// reusableDefinition a parameter or response declared in the general API info, e.g. @parameters.Page.
+type reusableDefinition struct {
+	attribute string
+	value     string
+	file      *ast.File
+}
+
+// OperationAnnotationHandler handles a custom operation annotation such as @RateLimit.
+// attribute is the annotation as written, lineRemainder the rest of the comment line.
+type OperationAnnotationHandler func(attribute, lineRemainder string, astFile *ast.File, operation *Operation) error
+
 // FieldParserFactory create FieldParser.
 type FieldParserFactory func(ps *Parser, field *ast.Field) FieldParser
 
@@ -225,6 +272,10 @@ func New(options ...func(*Parser)) *Parser {
 		tags:               make(map[string]struct{}),
 		fieldParserFactory: newTagBaseFieldParser,
 		Overrides:          make(map[string]string),
+
+		operationAnnotationHandlers: make(map[string]OperationAnnotationHandler),
+		nestedParamSeparator:        ".",
+		wellKnownTypes:              defaultWellKnownTypes(),
 	}
 
 	for _, option := range options {
@@ -299,6 +350,16 @@ func SetStrict(strict bool) func(*Parser) {
 	}
 }
 
+// SetNestedParamSeparator sets the separator joining the names of nested struct fields expanded into params,
+// e.g. filter.name.
+func SetNestedParamSeparator(separator string) func(*Parser) {
+	return func(p *Parser) {
+		if separator != "" {
+			p.nestedParamSeparator = separator
+		}
+	}
+}
+
 // SetDebugger allows the use of user-defined implementations.
 func SetDebugger(logger Debugger) func(parser *Parser) {
 	return func(p *Parser) {
@@ -315,6 +376,23 @@ func SetFieldParserFactory(factory FieldParserFactory) func(parser *Parser) {
 	}
 }
 
+// SetOperationAnnotationHandler registers handler for the custom operation annotation attribute, e.g. "@RateLimit".
+// Attributes are matched case-insensitively, and built-in annotations take precedence.
+func SetOperationAnnotationHandler(attribute string, handler OperationAnnotationHandler) func(parser *Parser) {
+	return func(p *Parser) {
+		p.operationAnnotationHandlers[strings.ToLower(attribute)] = handler
+	}
+}
+
+// SetFileSystem reads Go sources, markdown files and code examples from fsys instead of the OS file system.
+// Search dirs and file paths are then fs.FS paths, e.g. "." or "api/main.go". Parsing dependencies still
+// requires the go tool and is not supported.
+func SetFileSystem(fsys fs.FS) func(parser *Parser) {
+	return func(p *Parser) {
+		p.fsys = fsys
+	}
+}
+
 // SetOverrides allows the use of user-defined global type overrides.
 func SetOverrides(overrides map[string]string) func(parser *Parser) {
 	return func(p *Parser) {
@@ -341,7 +419,11 @@ func (parser *Parser) ParseAPIMultiSearchDir(searchDirs []string, mainAPIFile st
 	for _, searchDir := range searchDirs {
 		parser.debug.Printf("Generate general API Info, search dir:%s", searchDir)
 
-		packageDir, err := getPkgName(searchDir)
+		if parser.fsys != nil && !fs.ValidPath(fsPath(searchDir)) {
+			return fmt.Errorf("search dir %s is not a valid path of the file system", searchDir)
+		}
+
+		packageDir, err := parser.getPkgName(searchDir)
 		if err != nil {
 			parser.debug.Printf("warning: failed to get package name in dir: %s, error: %s", searchDir, err.Error())
 		}
@@ -352,13 +434,22 @@ func (parser *Parser) ParseAPIMultiSearchDir(searchDirs []string, mainAPIFile st
 		}
 	}
 
-	absMainAPIFilePath, err := filepath.Abs(filepath.Join(searchDirs[0], mainAPIFile))
-	if err != nil {
-		return err
+	absMainAPIFilePath := fsPath(path.Join(filepath.ToSlash(searchDirs[0]), filepath.ToSlash(mainAPIFile)))
+	if parser.fsys == nil {
+		var err error
+
+		absMainAPIFilePath, err = filepath.Abs(filepath.Join(searchDirs[0], mainAPIFile))
+		if err != nil {
+			return err
+		}
+	}
+
+	if parser.ParseDependency && parser.fsys != nil {
+		parser.debug.Printf("warning: parsing dependencies is not supported with a custom file system")
 	}
 
 	// Use 'go list' command instead of depth.Resolve()
-	if parser.ParseDependency {
+	if parser.ParseDependency && parser.fsys == nil {
 		if parser.parseGoList {
 			pkgs, err := listPackages(context.Background(), filepath.Dir(absMainAPIFilePath), nil, "-deps")
 			if err != nil {
@@ -395,7 +486,7 @@ func (parser *Parser) ParseAPIMultiSearchDir(searchDirs []string, mainAPIFile st
 		}
 	}
 
-	err = parser.ParseGeneralAPIInfo(absMainAPIFilePath)
+	err := parser.ParseGeneralAPIInfo(absMainAPIFilePath)
 	if err != nil {
 		return err
 	}
@@ -405,12 +496,101 @@ func (parser *Parser) ParseAPIMultiSearchDir(searchDirs []string, mainAPIFile st
 		return err
 	}
 
+	err = parser.parseReusableDefinitions()
+	if err != nil {
+		return err
+	}
+
 	err = parser.packages.RangeFiles(parser.ParseRouterAPIInfo)
 	if err != nil {
 		return err
 	}
 
-	return parser.checkOperationIDUniqueness()
+	err = parser.checkOperationIDUniqueness()
+	if err != nil {
+		return err
+	}
+
+	return parser.checkSecurityDefinitions()
+}
+
+// ParseTypeDefinitions parses the Go files in searchDirs and collects their type definitions, without general API
+// info or operations. Single annotations can then be checked with NewOperation, e.g. by an editor integration.
+// The reusable parameters and responses of a general API info parsed before with ParseGeneralAPIInfo are
+// resolved too, so that references to them can be checked.
+func (parser *Parser) ParseTypeDefinitions(searchDirs ...string) error {
+	for _, searchDir := range searchDirs {
+		packageDir, err := parser.getPkgName(searchDir)
+		if err != nil {
+			parser.debug.Printf("warning: failed to get package name in dir: %s, error: %s", searchDir, err.Error())
+		}
+
+		err = parser.getAllGoFileInfo(packageDir, searchDir)
+		if err != nil {
+			return err
+		}
+	}
+
+	var err error
+
+	parser.parsedSchemas, err = parser.packages.ParseTypes()
+	if err != nil {
+		return err
+	}
+
+	return parser.parseReusableDefinitions()
+}
+
+// Packages returns the packages definitions collected by the parser.
+func (parser *Parser) Packages() *PackagesDefinitions {
+	return parser.packages
+}
+
+// getPkgName returns the import path of the package in searchDir. With a custom file system it is derived
+// from the closest go.mod, as the go tool cannot be used.
+func (parser *Parser) getPkgName(searchDir string) (string, error) {
+	if parser.fsys == nil {
+		return getPkgName(searchDir)
+	}
+
+	dir := fsPath(searchDir)
+
+	for modDir := dir; ; modDir = path.Dir(modDir) {
+		data, err := fs.ReadFile(parser.fsys, path.Join(modDir, "go.mod"))
+		if err == nil {
+			modulePath := modfileModulePath(data)
+			if modulePath == "" {
+				return "", fmt.Errorf("no module directive in %s", path.Join(modDir, "go.mod"))
+			}
+
+			if modDir == dir {
+				return modulePath, nil
+			}
+
+			if modDir == "." {
+				return modulePath + "/" + dir, nil
+			}
+
+			return modulePath + "/" + strings.TrimPrefix(dir, modDir+"/"), nil
+		}
+
+		// the root of the file system, or "/" of a rooted path which isn't valid in one
+		if modDir == "." || path.Dir(modDir) == modDir {
+			return dir, nil
+		}
+	}
+}
+
+// modfileModulePath returns the module path declared in the content of a go.mod file.
+func modfileModulePath(data []byte) string {
+	for _, line := range strings.Split(string(data), "\n") {
+		fields := strings.Fields(line)
+		if len(fields) >= 2 && fields[0] == "module" {
+			return strings.Trim(fields[1], `"`)
+		}
+	}
+
+	return ""
 }
 
 func getPkgName(searchDir string) (string, error) {
@@ -441,12 +621,24 @@ func getPkgName(searchDir string) (string, error) {
 
 // ParseGeneralAPIInfo parses general api info for given mainAPIFile path.
 func (parser *Parser) ParseGeneralAPIInfo(mainAPIFile string) error {
-	fileTree, err := goparser.ParseFile(token.NewFileSet(), mainAPIFile, nil, goparser.ParseComments)
+	var src interface{}
+
+	if parser.fsys != nil {
+		data, err := readFile(parser.fsys, mainAPIFile)
+		if err != nil {
+			return fmt.Errorf("cannot parse source files %s: %s", mainAPIFile, err)
+		}
+
+		src = data
+	}
+
+	fileTree, err := goparser.ParseFile(token.NewFileSet(), mainAPIFile, src, goparser.ParseComments)
 	if err != nil {
 		return fmt.Errorf("cannot parse source files %s: %s", mainAPIFile, err)
 	}
 
 	parser.swagger.Swagger = "2.0"
+	parser.generalAPIFile = fileTree
 
 	for _, comment := range fileTree.Comments {
 		comments := strings.Split(comment.Text(), "\n")
@@ -493,7 +685,7 @@ func parseGeneralAPIInfo(parser *Parser, comments []string) error {
 
 			setSwaggerInfo(parser.swagger, attr, value)
 		case descriptionMarkdownAttr:
-			commentInfo, err := getMarkdownForTag("api", parser.markdownFileDir)
+			commentInfo, err := getMarkdownForTag(parser.fsys, "api", parser.markdownFileDir)
 			if err != nil {
 				return err
 			}
@@ -530,7 +722,7 @@ func parseGeneralAPIInfo(parser *Parser, comments []string) error {
 		case "@tag.description.markdown":
 			tag := parser.swagger.Tags[len(parser.swagger.Tags)-1]
 
-			commentInfo, err := getMarkdownForTag(tag.TagProps.Name, parser.markdownFileDir)
+			commentInfo, err := getMarkdownForTag(parser.fsys, tag.TagProps.Name, parser.markdownFileDir)
 			if err != nil {
 				return err
 			}
@@ -562,6 +754,9 @@ func parseGeneralAPIInfo(parser *Parser, comments []string) error {
 
 			parser.swagger.SecurityDefinitions[value] = scheme
 
+		case securityAttr:
+			parser.swagger.Security = append(parser.swagger.Security, parseSecurity(value)...)
+
 		case "@query.collection.format":
 			parser.collectionFormatInQuery = TransToValidCollectionFormat(value)
 
@@ -577,6 +772,17 @@ func parseGeneralAPIInfo(parser *Parser, comments []string) error {
 			}
 
 		default:
+			if strings.HasPrefix(attr, parametersAttrPrefix) || strings.HasPrefix(attr, responsesAttrPrefix) ||
+				attr == defaultResponseAttr || attr == defaultHeaderAttr {
+				parser.reusableDefinitions = append(parser.reusableDefinitions, reusableDefinition{
+					attribute: attribute,
+					value:     value,
+					file:      parser.generalAPIFile,
+				})
+
+				break
+			}
+
 			if strings.HasPrefix(attribute, "@x-") {
 				extensionName := attribute[1:]
 
@@ -624,6 +830,69 @@ func parseGeneralAPIInfo(parser *Parser, comments []string) error {
 	return nil
 }
 
+// parseReusableDefinitions parses the named parameters and responses of the general API info,
+// operations reference them with e.g. `@Param ref:Page` or `@Failure 500 ref:InternalError`,
+// and the default responses merged into the operations.
+func (parser *Parser) parseReusableDefinitions() error {
+	for _, def := range parser.reusableDefinitions {
+		operation := NewOperation(parser)
+
+		attr := strings.ToLower(def.attribute)
+
+		switch {
+		case strings.HasPrefix(attr, parametersAttrPrefix):
+			name := def.attribute[len(parametersAttrPrefix):]
+
+			err := operation.ParseParamComment(def.value, def.file)
+			if err != nil {
+				return fmt.Errorf("%s: %w", def.attribute, err)
+			}
+
+			if len(operation.Parameters) != 1 {
+				return fmt.Errorf("%s must declare exactly one parameter", def.attribute)
+			}
+
+			if parser.swagger.Parameters == nil {
+				parser.swagger.Parameters = make(map[string]spec.Parameter)
+			}
+
+			parser.swagger.Parameters[name] = operation.Parameters[0]
+		case strings.HasPrefix(attr, responsesAttrPrefix):
+			name := def.attribute[len(responsesAttrPrefix):]
+
+			fields := strings.Fields(def.value)
+			if len(fields) == 0 {
+				return fmt.Errorf("%s needs a response, e.g. {object} model.APIError \"description\"", def.attribute)
+			}
+
+			if _, err := strconv.Atoi(fields[0]); err == nil || strings.EqualFold(fields[0], defaultTag) {
+				return fmt.Errorf("%s takes no status code, remove %s", def.attribute, fields[0])
+			}
+
+			// a named response has no status code, parse it as the default response
+			err := operation.ParseResponseComment(strings.TrimSpace(defaultTag+" "+def.value), def.file)
+			if err != nil {
+				return fmt.Errorf("%s: %w", def.attribute, err)
+			}
+
+			if parser.swagger.Responses == nil {
+				parser.swagger.Responses = make(map[string]spec.Response)
+			}
+
+			parser.swagger.Responses[name] = *operation.Responses.Default
+		default:
+			response, err := parseDefaultResponse(operation, attr == defaultHeaderAttr, def.value, def.file)
+			if err != nil {
+				return fmt.Errorf("%s: %w", def.attribute, err)
+			}
+
+			parser.defaultResponses = append(parser.defaultResponses, response)
+		}
+	}
+
+	return nil
+}
+
 func setSwaggerInfo(swagger *spec.Swagger, attribute, value string) {
 	switch attribute {
 	case versionAttr:
@@ -720,8 +989,8 @@ func parseSecAttributes(context string, lines []string, index *int) (*spec.Secur
 			description = value
 		}
 
-		// next securityDefinitions
-		if strings.Index(securityAttr, "@securitydefinitions.") == 0 {
+		// next securityDefinitions, or the global security requirement
+		if strings.Index(securityAttr, "@securitydefinitions.") == 0 || securityAttr == "@security" {
 			// Go back to the previous line and break
 			*index--
 
@@ -796,8 +1065,8 @@ func isGeneralAPIComment(comments []string) bool {
 	return true
 }
 
-func getMarkdownForTag(tagName string, dirPath string) ([]byte, error) {
-	dirEntries, err := os.ReadDir(dirPath)
+func getMarkdownForTag(fsys fs.FS, tagName string, dirPath string) ([]byte, error) {
+	dirEntries, err := readDir(fsys, dirPath)
 	if err != nil {
 		return nil, err
 	}
@@ -816,7 +1085,7 @@ func getMarkdownForTag(tagName string, dirPath string) ([]byte, error) {
 		if strings.Contains(fileName, tagName) {
 			fullPath := filepath.Join(dirPath, fileName)
 
-			commentInfo, err := os.ReadFile(fullPath)
+			commentInfo, err := readFile(fsys, fullPath)
 			if err != nil {
 				return nil, fmt.Errorf("Failed to read markdown file %s error: %s ", fullPath, err)
 			}
@@ -896,26 +1165,51 @@ func matchExtension(extensionToMatch string, comments []*ast.Comment) (match boo
 
 // ParseRouterAPIInfo parses router api info for given astFile.
 func (parser *Parser) ParseRouterAPIInfo(fileInfo *AstFileInfo) error {
+	if (fileInfo.ParseFlag & ParseOperations) == ParseNone {
+		return nil
+	}
+
+	defaults, err := parser.getPackageDefaults(fileInfo)
+	if err != nil {
+		return err
+	}
+
 	for _, astDescription := range fileInfo.File.Decls {
-		if (fileInfo.ParseFlag & ParseOperations) == ParseNone {
-			continue
-		}
 		astDeclaration, ok := astDescription.(*ast.FuncDecl)
 		if ok && astDeclaration.Doc != nil && astDeclaration.Doc.List != nil {
-			if parser.matchTags(astDeclaration.Doc.List) &&
+			if parser.matchTags(defaults.withTags(astDeclaration.Doc.List)) &&
 				matchExtension(parser.parseExtension, astDeclaration.Doc.List) {
+				unknownAttributes := parser.CheckOperationAnnotations(astDeclaration.Doc)
+
 				// for per 'function' comment, create a new 'Operation' object
 				operation := NewOperation(parser, SetCodeExampleFilesDirectory(parser.codeExampleFilesDir))
 				for _, comment := range astDeclaration.Doc.List {
+					if err, ok := unknownAttributes[comment]; ok {
+						if parser.Strict {
+							return fmt.Errorf("ParseComment error in file %s :%+v", fileInfo.Path, err)
+						}
+
+						if fileInfo.FileSet != nil {
+							parser.debug.Printf("warning: %s:%d: %s", fileInfo.Path, fileInfo.FileSet.Position(comment.Slash).Line, err)
+						} else {
+							parser.debug.Printf("warning: %s: %s", fileInfo.Path, err)
+						}
+					}
+
 					err := operation.ParseComment(comment.Text, fileInfo.File)
 					if err != nil {
 						return fmt.Errorf("ParseComment error in file %s :%+v", fileInfo.Path, err)
 					}
 				}
+				defaults.apply(operation)
+				operation.addMultipartConsumes()
+
 				err := processRouterOperation(parser, operation)
 				if err != nil {
 					return err
 				}
+
+				parser.addParsedOperations(fileInfo, astDeclaration, operation)
 			}
 		}
 	}
@@ -923,6 +1217,119 @@ func (parser *Parser) ParseRouterAPIInfo(fileInfo *AstFileInfo) error {
 	return nil
 }
 
+// CheckOperationAnnotations returns an error for every unknown @ attribute of doc, keyed by comment, with a
+// suggestion when it looks like a misspelled one. Only comment blocks with operation annotations, e.g. @Router or
+// @Param, are checked. Extensions and attributes with an OperationAnnotationHandler are known.
+func (parser *Parser) CheckOperationAnnotations(doc *ast.CommentGroup) map[*ast.Comment]error {
+	if doc == nil {
+		return nil
+	}
+
+	var (
+		isOperation bool
+		attributes  = make(map[*ast.Comment]string)
+	)
+
+	for _, comment := range doc.List {
+		commentLine := strings.TrimSpace(strings.TrimLeft(comment.Text, "/"))
+		if !strings.HasPrefix(commentLine, "@") {
+			continue
+		}
+
+		attribute := strings.Fields(commentLine)[0]
+		lowerAttribute := strings.ToLower(attribute)
+
+		if findInSlice(operationOnlyAttributes, lowerAttribute) {
+			isOperation = true
+		}
+
+		_, known := operationAttributes[lowerAttribute]
+		_, handled := parser.operationAnnotationHandlers[lowerAttribute]
+
+		if !known && !handled && !strings.HasPrefix(lowerAttribute, "@x-") {
+			attributes[comment] = attribute
+		}
+	}
+
+	if !isOperation || len(attributes) == 0 {
+		return nil
+	}
+
+	errs := make(map[*ast.Comment]error, len(attributes))
+
+	for comment, attribute := range attributes {
+		suggestion := suggestOperationAttribute(strings.ToLower(attribute))
+		if suggestion == "" {
+			errs[comment] = fmt.Errorf("unknown annotation %s", attribute)
+		} else {
+			errs[comment] = fmt.Errorf("unknown annotation %s, did you mean %s?", attribute, suggestion)
+		}
+	}
+
+	return errs
+}
+
+// suggestOperationAttribute returns the known attribute closest to lowerAttribute, if it is close enough to be
+// a misspelling of it.
+func suggestOperationAttribute(lowerAttribute string) string {
+	var (
+		suggestion   string
+		bestDistance int
+	)
+
+	for known, spelling := range operationAttributes {
+		distance := editDistance(lowerAttribute, known)
+		if distance > len(known)/3 {
+			continue
+		}
+
+		if suggestion == "" || distance < bestDistance || distance == bestDistance && spelling < suggestion {
+			suggestion, bestDistance = spelling, distance
+		}
+	}
+
+	return suggestion
+}
+
+func (parser *Parser) addParsedOperations(fileInfo *AstFileInfo, funcDecl *ast.FuncDecl, operation *Operation) {
+	var receiver string
+	if funcDecl.Recv != nil && len(funcDecl.Recv.List) > 0 {
+		receiver = types.ExprString(funcDecl.Recv.List[0].Type)
+	}
+
+	comments := make([]string, 0, len(funcDecl.Doc.List))
+	for _, comment := range funcDecl.Doc.List {
+		comments = append(comments, comment.Text)
+	}
+
+	var startLine, endLine int
+	if fileInfo.FileSet != nil {
+		startLine = fileInfo.FileSet.Position(funcDecl.Pos()).Line
+		endLine = fileInfo.FileSet.Position(funcDecl.End()).Line
+	}
+
+	for _, routeProperties := range operation.RouterProperties {
+		parser.operations = append(parser.operations, ParsedOperation{
+			Method:      routeProperties.HTTPMethod,
+			Path:        routeProperties.Path,
+			Operation:   &operation.Operation,
+			FuncName:    funcDecl.Name.Name,
+			Receiver:    receiver,
+			PackagePath: fileInfo.PackagePath,
+			File:        fileInfo.Path,
+			StartLine:   startLine,
+			EndLine:     endLine,
+			Comments:    comments,
+		})
+	}
+}
+
+// Operations returns the operations parsed by ParseAPI, ordered by file and by declaration within a file.
+// An operation with several @Router annotations is returned once per route.
+func (parser *Parser) Operations() []ParsedOperation {
+	return parser.operations
+}
+
 func refRouteMethodOp(item *spec.PathItem, method string) (op **spec.Operation) {
 	switch method {
 	case http.MethodGet:
@@ -945,6 +1352,8 @@ func refRouteMethodOp(item *spec.PathItem, method string) (op **spec.Operation)
 }
 
 func processRouterOperation(parser *Parser, operation *Operation) error {
+	parser.applyDefaultResponses(operation)
+
 	for _, routeProperties := range operation.RouterProperties {
 		var (
 			pathItem spec.PathItem
@@ -1005,6 +1414,10 @@ func (parser *Parser) getTypeSchema(typeName string, file *ast.File, ref bool) (
 		return PrimitiveSchema(TransToValidSchemeType(typeName)), nil
 	}
 
+	if schema, ok := parser.getWellKnownTypeSchema(typeName, file); ok {
+		return schema, nil
+	}
+
 	schemaType, err := convertFromSpecificToPrimitive(typeName)
 	if err == nil {
 		return PrimitiveSchema(schemaType), nil
@@ -1114,16 +1527,39 @@ func (parser *Parser) ParseDefinition(typeSpecDef *TypeSpecDef) (*Schema, error)
 
 	parser.debug.Printf("Generating %s", typeName)
 
-	definition, err := parser.parseTypeExpr(typeSpecDef.File, typeSpecDef.TypeSpec.Type, false)
+	// a type which encodes itself isn't described by its declaration, nor by its enum consts
+	definition, err := parser.marshalerSchema(typeSpecDef)
 	if err != nil {
 		return nil, err
 	}
 
+	encodesItself := definition != nil
+
+	if !encodesItself {
+		definition, err = parser.parseTypeExpr(typeSpecDef.File, typeSpecDef.TypeSpec.Type, false)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	var poly *polymorphism
+
+	if _, ok := typeSpecDef.TypeSpec.Type.(*ast.InterfaceType); ok {
+		poly, err = parsePolymorphism(typeSpecDef)
+		if err != nil {
+			return nil, err
+		}
+
+		if poly != nil {
+			definition = poly.schema()
+		}
+	}
+
 	if definition.Description == "" {
 		fillDefinitionDescription(definition, typeSpecDef.File, typeSpecDef)
 	}
 
-	if len(typeSpecDef.Enums) > 0 {
+	if len(typeSpecDef.Enums) > 0 && !encodesItself {
 		var varnames []string
 		var enumComments = make(map[string]string)
 		for _, value := range typeSpecDef.Enums {
@@ -1155,6 +1591,13 @@ func (parser *Parser) ParseDefinition(typeSpecDef *TypeSpecDef) (*Schema, error)
 		parser.swagger.Definitions[s2.Name] = *definition
 	}
 
+	if poly != nil {
+		err = parser.parseImplementations(typeSpecDef, &sch, poly)
+		if err != nil {
+			return nil, err
+		}
+	}
+
 	return &sch, nil
 }
 
@@ -1535,6 +1978,10 @@ func defineTypeOfExample(schemaType, arrayType, exampleValue string) (interface{
 
 // GetAllGoFileInfo gets all Go source files information for given searchDir.
 func (parser *Parser) getAllGoFileInfo(packageDir, searchDir string) error {
+	if parser.fsys != nil {
+		return parser.getAllGoFileInfoFromFS(packageDir, searchDir)
+	}
+
 	return filepath.Walk(searchDir, func(path string, f os.FileInfo, _ error) error {
 		err := parser.Skip(path, f)
 		if err != nil {
@@ -1554,6 +2001,43 @@ func (parser *Parser) getAllGoFileInfo(packageDir, searchDir string) error {
 	})
 }
 
+// getAllGoFileInfoFromFS is getAllGoFileInfo for a custom file system.
+func (parser *Parser) getAllGoFileInfoFromFS(packageDir, searchDir string) error {
+	root := fsPath(searchDir)
+
+	return fs.WalkDir(parser.fsys, root, func(filePath string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+
+		f, err := d.Info()
+		if err != nil {
+			return err
+		}
+
+		err = parser.Skip(filePath, f)
+		if err != nil {
+			return err
+		}
+
+		if f.IsDir() || strings.HasSuffix(strings.ToLower(filePath), "_test.go") || path.Ext(filePath) != ".go" {
+			return nil
+		}
+
+		src, err := fs.ReadFile(parser.fsys, filePath)
+		if err != nil {
+			return err
+		}
+
+		relDir := strings.TrimPrefix(strings.TrimPrefix(path.Dir(filePath), root), "/")
+		if root == "." {
+			relDir = path.Dir(filePath)
+		}
+
+		return parser.parseFile(path.Clean(path.Join(packageDir, relDir)), filePath, src, ParseAll)
+	})
+}
+
 func (parser *Parser) getAllGoFileInfoFromDeps(pkg *depth.Pkg) error {
 	ignoreInternal := pkg.Internal && !parser.ParseInternal
 	if ignoreInternal || !pkg.Resolved { // ignored internal and not resolved dependencies
@@ -1635,6 +2119,42 @@ func (parser *Parser) checkOperationIDUniqueness() error {
 	return nil
 }
 
+// checkSecurityDefinitions checks that the global and operation security requirements only use defined schemes.
+func (parser *Parser) checkSecurityDefinitions() error {
+	err := parser.checkSecurityRequirement(parser.swagger.Security, "general API info")
+	if err != nil {
+		return err
+	}
+
+	for _, op := range parser.operations {
+		err = parser.checkSecurityRequirement(op.Operation.Security, fmt.Sprintf("%s %s", op.Method, op.Path))
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func (parser *Parser) checkSecurityRequirement(security []map[string][]string, location string) error {
+	for _, requirement := range security {
+		names := make([]string, 0, len(requirement))
+		for name := range requirement {
+			names = append(names, name)
+		}
+
+		sort.Strings(names)
+
+		for _, name := range names {
+			if _, ok := parser.swagger.SecurityDefinitions[name]; !ok {
+				return fmt.Errorf("security definition '%s' used in '%s' is not defined", name, location)
+			}
+		}
+	}
+
+	return nil
+}
+
 // Skip returns filepath.SkipDir error if match vendor and hidden folder.
 func (parser *Parser) Skip(path string, f os.FileInfo) error {
 	return walkWith(parser.excludes, parser.ParseVendor)(path, f)
@@ -16,6 +16,7 @@ import (
 
 	"github.com/go-openapi/spec"
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 )
 
 const defaultParseDepth = 100
@@ -1213,7 +1214,8 @@ func TestParseSimpleApi_ForSnakecase(t *testing.T) {
                 },
                 "data": {},
                 "decimal": {
-                    "type": "number"
+                    "type": "string",
+                    "format": "decimal"
                 },
                 "id": {
                     "type": "integer",
@@ -1270,7 +1272,8 @@ func TestParseSimpleApi_ForSnakecase(t *testing.T) {
                     }
                 },
                 "uuid": {
-                    "type": "string"
+                    "type": "string",
+                    "format": "uuid"
                 }
             }
         },
@@ -1674,7 +1677,8 @@ func TestParseSimpleApi_ForLowerCamelcase(t *testing.T) {
                 },
                 "data": {},
                 "decimal": {
-                    "type": "number"
+                    "type": "string",
+                    "format": "decimal"
                 },
                 "id": {
                     "type": "integer",
@@ -1726,7 +1730,8 @@ func TestParseSimpleApi_ForLowerCamelcase(t *testing.T) {
                     }
                 },
                 "uuid": {
-                    "type": "string"
+                    "type": "string",
+                    "format": "uuid"
                 }
             }
         },
@@ -2199,6 +2204,19 @@ func TestParseDuplicatedFunctionScoped(t *testing.T) {
 	assert.Errorf(t, err, "duplicated @id declarations successfully found")
 }
 
+func TestParseSecurity(t *testing.T) {
+	t.Parallel()
+
+	searchDir := "testdata/security"
+	p := New()
+	err := p.ParseAPI(searchDir, mainAPIFile, defaultParseDepth)
+	assert.NoError(t, err)
+	b, _ := json.MarshalIndent(p.swagger, "", "    ")
+	expected, err := os.ReadFile(filepath.Join(searchDir, "expected.json"))
+	assert.NoError(t, err)
+	assert.Equal(t, string(expected), string(b))
+}
+
 func TestParseConflictSchemaName(t *testing.T) {
 	t.Parallel()
 
@@ -2665,6 +2683,270 @@ func Test(){
 	assert.Error(t, err)
 }
 
+func TestParser_ParseRouterApiUnknownAnnotation(t *testing.T) {
+	t.Parallel()
+
+	src := `
+package test
+
+// @Summary List pets
+// @Sucess 200 {string} string "ok"
+// @Parm id path int true "ID"
+// @RateLimit 10
+// @x-internal true
+// @Router /pets [get]
+func Test(){
+}
+
+// Main godoc
+// @title Petstore
+// @description a sample
+func main(){
+}
+`
+	logger := &testLogger{}
+
+	p := New(SetDebugger(logger))
+	err := p.packages.ParseFile("api", "api/api.go", src, ParseAll)
+	assert.NoError(t, err)
+
+	err = p.packages.RangeFiles(p.ParseRouterAPIInfo)
+	assert.NoError(t, err)
+	assert.NotNil(t, p.swagger.Paths.Paths["/pets"].Get)
+
+	path, _ := filepath.Abs("api/api.go")
+	assert.Equal(t, []string{
+		"warning: " + path + ":5: unknown annotation @Sucess, did you mean @Success?",
+		"warning: " + path + ":6: unknown annotation @Parm, did you mean @Param?",
+		"warning: " + path + ":7: unknown annotation @RateLimit",
+	}, logger.Messages)
+
+	p = New(SetStrict(true), SetOperationAnnotationHandler("@RateLimit", func(string, string, *ast.File, *Operation) error {
+		return nil
+	}))
+	err = p.packages.ParseFile("api", "api/api.go", src, ParseAll)
+	assert.NoError(t, err)
+
+	err = p.packages.RangeFiles(p.ParseRouterAPIInfo)
+	assert.EqualError(t, err, "ParseComment error in file "+path+" :unknown annotation @Sucess, did you mean @Success?")
+}
+
+func TestParser_ParseRouterApiUnknownAnnotationWithoutFileSet(t *testing.T) {
+	t.Parallel()
+
+	src := `
+package test
+
+// @Sucess 200 {string} string "ok"
+// @Router /pets [get]
+func Test(){
+}
+`
+	f, err := goparser.ParseFile(token.NewFileSet(), "", src, goparser.ParseComments)
+	require.NoError(t, err)
+
+	logger := &testLogger{}
+
+	p := New(SetDebugger(logger))
+	err = p.ParseRouterAPIInfo(&AstFileInfo{File: f, Path: "api/api.go", PackagePath: "api", ParseFlag: ParseAll})
+	assert.NoError(t, err)
+	assert.Equal(t, []string{
+		"warning: api/api.go: unknown annotation @Sucess, did you mean @Success?",
+	}, logger.Messages)
+}
+
+func TestParser_ParseGlobalSecurity(t *testing.T) {
+	t.Parallel()
+
+	src := `
+package test
+
+// @Summary List pets
+// @Router /pets [get]
+func List(){
+}
+
+// @Summary Health check
+// @Security none
+// @Router /health [get]
+func Health(){
+}
+
+// @Summary Delete a pet
+// @Security ApiKeyAuth && OAuth2[admin]
+// @Router /pets [delete]
+func Delete(){
+}
+`
+	p := New()
+	err := parseGeneralAPIInfo(p, []string{
+		"@securityDefinitions.apikey ApiKeyAuth",
+		"@in header",
+		"@name Authorization",
+		"@securitydefinitions.oauth2.implicit OAuth2",
+		"@authorizationurl https://example.com/oauth/authorize",
+		"@scope.admin Grants admin access",
+		"@Security ApiKeyAuth || OAuth2[admin]",
+	})
+	assert.NoError(t, err)
+	assert.Equal(t, []map[string][]string{{"ApiKeyAuth": {}}, {"OAuth2": {"admin"}}}, p.swagger.Security)
+
+	err = p.packages.ParseFile("api", "api/api.go", src, ParseAll)
+	assert.NoError(t, err)
+
+	err = p.packages.RangeFiles(p.ParseRouterAPIInfo)
+	assert.NoError(t, err)
+	assert.NoError(t, p.checkSecurityDefinitions())
+
+	assert.Nil(t, p.swagger.Paths.Paths["/pets"].Get.Security)
+	assert.Equal(t, []map[string][]string{}, p.swagger.Paths.Paths["/health"].Get.Security)
+	assert.Equal(t, []map[string][]string{{"ApiKeyAuth": {}, "OAuth2": {"admin"}}}, p.swagger.Paths.Paths["/pets"].Delete.Security)
+
+	p.swagger.Paths.Paths["/health"].Get.Security = append(p.swagger.Paths.Paths["/health"].Get.Security, map[string][]string{"Basic": {}})
+	assert.EqualError(t, p.checkSecurityDefinitions(), "security definition 'Basic' used in 'GET /health' is not defined")
+
+	p.swagger.Security = append(p.swagger.Security, map[string][]string{"Firebase": {}})
+	assert.EqualError(t, p.checkSecurityDefinitions(), "security definition 'Firebase' used in 'general API info' is not defined")
+}
+
+func TestParser_ReusableDefinitions(t *testing.T) {
+	t.Parallel()
+
+	src := `
+package api
+
+type APIError struct {
+	Message string
+}
+
+// @Summary List pets
+// @Param ref:Page
+// @Param name query string false "name"
+// @Success 200 {string} string "ok"
+// @Failure 500,default ref:InternalError
+// @Router /pets [get]
+func List(){
+}
+`
+	p := New()
+	err := parseGeneralAPIInfo(p, []string{
+		`@parameters.Page page query int false "page number" minimum(1)`,
+		`@responses.InternalError {object} api.APIError "internal server error"`,
+		`@responses.NoContent "no content"`,
+	})
+	assert.NoError(t, err)
+
+	err = p.packages.ParseFile("api", "api/api.go", src, ParseAll)
+	assert.NoError(t, err)
+
+	_, err = p.packages.ParseTypes()
+	assert.NoError(t, err)
+
+	err = p.parseReusableDefinitions()
+	assert.NoError(t, err)
+
+	err = p.packages.RangeFiles(p.ParseRouterAPIInfo)
+	assert.NoError(t, err)
+
+	b, _ := json.MarshalIndent(p.swagger, "", "    ")
+
+	expected := `{
+    "info": {
+        "contact": {}
+    },
+    "paths": {
+        "/pets": {
+            "get": {
+                "summary": "List pets",
+                "parameters": [
+                    {
+                        "$ref": "#/parameters/Page"
+                    },
+                    {
+                        "type": "string",
+                        "description": "name",
+                        "name": "name",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "ok",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "500": {
+                        "$ref": "#/responses/InternalError"
+                    },
+                    "default": {
+                        "$ref": "#/responses/InternalError"
+                    }
+                }
+            }
+        }
+    },
+    "definitions": {
+        "api.APIError": {
+            "type": "object",
+            "properties": {
+                "message": {
+                    "type": "string"
+                }
+            }
+        }
+    },
+    "parameters": {
+        "Page": {
+            "minimum": 1,
+            "type": "integer",
+            "description": "page number",
+            "name": "page",
+            "in": "query"
+        }
+    },
+    "responses": {
+        "InternalError": {
+            "description": "internal server error",
+            "schema": {
+                "$ref": "#/definitions/api.APIError"
+            }
+        },
+        "NoContent": {
+            "description": "no content"
+        }
+    }
+}`
+	assert.Equal(t, expected, string(b))
+
+	operation := NewOperation(p)
+	assert.EqualError(t, operation.ParseComment(`@Param ref:Limit`, nil),
+		"parameter Limit is not defined, declare it with @parameters.Limit")
+	assert.EqualError(t, operation.ParseComment(`@Failure 404 ref:NotFound`, nil),
+		"response NotFound is not defined, declare it with @responses.NotFound")
+
+	p = New()
+	err = parseGeneralAPIInfo(p, []string{`@parameters.Filter filter query api.Missing false "filter"`})
+	assert.NoError(t, err)
+	assert.Error(t, p.parseReusableDefinitions())
+
+	p = New()
+	err = parseGeneralAPIInfo(p, []string{`@responses.Empty`})
+	assert.NoError(t, err)
+	assert.EqualError(t, p.parseReusableDefinitions(),
+		`@responses.Empty needs a response, e.g. {object} model.APIError "description"`)
+
+	p = New()
+	err = parseGeneralAPIInfo(p, []string{`@responses.X 200 {object} string "x"`})
+	assert.NoError(t, err)
+	assert.EqualError(t, p.parseReusableDefinitions(), "@responses.X takes no status code, remove 200")
+
+	p = New()
+	err = parseGeneralAPIInfo(p, []string{`@responses.X default {object} string "x"`})
+	assert.NoError(t, err)
+	assert.EqualError(t, p.parseReusableDefinitions(), "@responses.X takes no status code, remove default")
+}
+
 func TestParser_ParseRouterApiGet(t *testing.T) {
 	t.Parallel()
 
@@ -2870,6 +3152,68 @@ func Test(){
 	assert.NotNil(t, val.Post)
 }
 
+func TestParser_Operations(t *testing.T) {
+	t.Parallel()
+
+	src := `
+package test
+
+type Controller struct{}
+
+// GetAccount godoc
+// @Summary Get an account
+// @Router /accounts/{id} [get]
+// @Router /v2/accounts/{id} [get]
+func (c *Controller) GetAccount() {
+}
+
+// NotAnOperation has no annotations.
+func NotAnOperation() {
+}
+
+// @Router /health [head]
+func Health() {}
+`
+	p := New()
+	err := p.packages.ParseFile("github.com/example/api", "api/api.go", src, ParseAll)
+	assert.NoError(t, err)
+
+	err = p.packages.RangeFiles(p.ParseRouterAPIInfo)
+	assert.NoError(t, err)
+
+	file, err := filepath.Abs("api/api.go")
+	assert.NoError(t, err)
+
+	operations := p.Operations()
+	require.Len(t, operations, 3)
+
+	assert.Equal(t, ParsedOperation{
+		Method:      "GET",
+		Path:        "/accounts/{id}",
+		Operation:   p.swagger.Paths.Paths["/accounts/{id}"].Get,
+		FuncName:    "GetAccount",
+		Receiver:    "*Controller",
+		PackagePath: "github.com/example/api",
+		File:        file,
+		StartLine:   10,
+		EndLine:     11,
+		Comments: []string{
+			"// GetAccount godoc",
+			"// @Summary Get an account",
+			"// @Router /accounts/{id} [get]",
+			"// @Router /v2/accounts/{id} [get]",
+		},
+	}, operations[0])
+	assert.Equal(t, "/v2/accounts/{id}", operations[1].Path)
+	assert.Same(t, operations[0].Operation, operations[1].Operation)
+
+	assert.Equal(t, "HEAD", operations[2].Method)
+	assert.Equal(t, "Health", operations[2].FuncName)
+	assert.Empty(t, operations[2].Receiver)
+	assert.Equal(t, 18, operations[2].StartLine)
+	assert.Equal(t, 18, operations[2].EndLine)
+}
+
 func TestParser_ParseRouterApiMultiple(t *testing.T) {
 	t.Parallel()
 
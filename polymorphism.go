@@ -0,0 +1,161 @@
+package swag
+
+import (
+	"fmt"
+	"go/ast"
+	"strings"
+
+	"github.com/go-openapi/spec"
+)
+
+const (
+	discriminatorAttr  = "@discriminator"
+	implementationAttr = "@implementation"
+
+	// discriminatorValueExtension the value of the discriminator of a subtype whose definition is named otherwise.
+	discriminatorValueExtension = "x-discriminator-value"
+)
+
+// polymorphism the implementations of an interface type and the property telling them apart, declared in the
+// doc comment of the interface. The value of an implementation defaults to the name of its definition.
+//
+//	// Event happened to a user.
+//	// @discriminator kind
+//	// @implementation created UserCreated
+//	// @implementation deleted UserDeleted
+//	type Event interface {
+//		isEvent()
+//	}
+type polymorphism struct {
+	discriminator   string
+	implementations []implementation
+}
+
+type implementation struct {
+	value    string
+	typeName string
+}
+
+// parsePolymorphism parses the polymorphism of an interface type, nil if its doc comment declares none.
+func parsePolymorphism(typeSpecDef *TypeSpecDef) (*polymorphism, error) {
+	var poly polymorphism
+
+	for _, comment := range typeDocComments(typeSpecDef) {
+		fields := strings.Fields(strings.TrimLeft(comment.Text, "/"))
+		if len(fields) == 0 {
+			continue
+		}
+
+		switch strings.ToLower(fields[0]) {
+		case discriminatorAttr:
+			if len(fields) != 2 {
+				return nil, fmt.Errorf("%s of %s needs a property name", discriminatorAttr, typeSpecDef.TypeName())
+			}
+
+			poly.discriminator = fields[1]
+		case implementationAttr:
+			switch len(fields) {
+			case 2:
+				poly.implementations = append(poly.implementations, implementation{typeName: fields[1]})
+			case 3:
+				poly.implementations = append(poly.implementations, implementation{value: fields[1], typeName: fields[2]})
+			default:
+				return nil, fmt.Errorf("%s of %s needs a type, optionally preceded by its discriminator value",
+					implementationAttr, typeSpecDef.TypeName())
+			}
+		}
+	}
+
+	switch {
+	case poly.discriminator == "" && len(poly.implementations) == 0:
+		return nil, nil
+	case poly.discriminator == "":
+		return nil, fmt.Errorf("%s of %s needs a %s", implementationAttr, typeSpecDef.TypeName(), discriminatorAttr)
+	case len(poly.implementations) == 0:
+		return nil, fmt.Errorf("%s of %s needs an %s", discriminatorAttr, typeSpecDef.TypeName(), implementationAttr)
+	}
+
+	return &poly, nil
+}
+
+// typeDocComments returns the doc comment of a type, the one of its declaration unless it is declared in a group.
+func typeDocComments(typeSpecDef *TypeSpecDef) []*ast.Comment {
+	if typeSpecDef.TypeSpec.Doc != nil {
+		return typeSpecDef.TypeSpec.Doc.List
+	}
+
+	for _, decl := range typeSpecDef.File.Decls {
+		genDecl, ok := decl.(*ast.GenDecl)
+		if !ok || genDecl.Doc == nil || len(genDecl.Specs) != 1 || genDecl.Specs[0] != typeSpecDef.TypeSpec {
+			continue
+		}
+
+		return genDecl.Doc.List
+	}
+
+	return nil
+}
+
+// parseImplementations defines the implementations of a polymorphic interface as subtypes of its definition,
+// i.e. allOf its definition and their own schema, and lists their discriminator values in the interface definition.
+func (parser *Parser) parseImplementations(typeSpecDef *TypeSpecDef, base *Schema, poly *polymorphism) error {
+	var values []interface{}
+
+	for _, impl := range poly.implementations {
+		implDef := parser.packages.FindTypeSpec(impl.typeName, typeSpecDef.File)
+		if implDef == nil {
+			return fmt.Errorf("cannot find type definition: %s", impl.typeName)
+		}
+
+		schema, err := parser.ParseDefinition(implDef)
+		if err != nil {
+			return err
+		}
+
+		value := impl.value
+		if value == "" {
+			value = schema.Name
+		}
+
+		subtype := new(spec.Schema).WithAllOf(*RefSchema(base.Name), *schema.Schema)
+		if value != schema.Name {
+			subtype.AddExtension(discriminatorValueExtension, value)
+		}
+
+		*schema.Schema = *subtype
+		parser.outputDefinition(implDef, schema)
+
+		values = append(values, value)
+	}
+
+	property := base.Schema.Properties[poly.discriminator]
+	property.Enum = values
+	base.Schema.Properties[poly.discriminator] = property
+
+	parser.outputDefinition(typeSpecDef, base)
+
+	return nil
+}
+
+// outputDefinition adds the definition of a type to the spec, or updates it if it is already there.
+func (parser *Parser) outputDefinition(typeSpecDef *TypeSpecDef, schema *Schema) {
+	if _, ok := parser.outputSchemas[typeSpecDef]; ok {
+		parser.swagger.Definitions[schema.Name] = *schema.Schema
+
+		return
+	}
+
+	parser.getRefTypeSchema(typeSpecDef, schema)
+}
+
+// schema the definition of a polymorphic interface, an object whose discriminator property is required.
+func (poly *polymorphism) schema() *spec.Schema {
+	schema := PrimitiveSchema(OBJECT)
+	schema.Discriminator = poly.discriminator
+	schema.Required = []string{poly.discriminator}
+	schema.Properties = spec.SchemaProperties{
+		poly.discriminator: *PrimitiveSchema(STRING),
+	}
+
+	return schema
+}
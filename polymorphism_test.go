@@ -0,0 +1,151 @@
+package swag
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParser_Polymorphism(t *testing.T) {
+	t.Parallel()
+
+	src := `
+package api
+
+// Event happened to a user.
+// @discriminator kind
+// @implementation created UserCreated
+// @implementation UserDeleted
+type Event interface {
+	isEvent()
+}
+
+type UserCreated struct {
+	Kind string ` + "`json:\"kind\"`" + `
+	Name string ` + "`json:\"name\"`" + `
+}
+
+type UserDeleted struct {
+	Kind string ` + "`json:\"kind\"`" + `
+	ID   int    ` + "`json:\"id\"`" + `
+}
+
+type Notification struct {
+	Event Event ` + "`json:\"event\"`" + `
+}
+
+// @Success 200 {object} Notification
+// @Router /notifications [get]
+func List(){
+}
+`
+	p := New()
+	require.NoError(t, p.packages.ParseFile("api", "api/api.go", src, ParseAll))
+
+	_, err := p.packages.ParseTypes()
+	require.NoError(t, err)
+
+	require.NoError(t, p.packages.RangeFiles(p.ParseRouterAPIInfo))
+
+	b, _ := json.MarshalIndent(p.swagger.Definitions, "", "    ")
+	expected := `{
+    "api.Event": {
+        "type": "object",
+        "required": [
+            "kind"
+        ],
+        "properties": {
+            "kind": {
+                "type": "string",
+                "enum": [
+                    "created",
+                    "api.UserDeleted"
+                ]
+            }
+        },
+        "discriminator": "kind"
+    },
+    "api.Notification": {
+        "type": "object",
+        "properties": {
+            "event": {
+                "$ref": "#/definitions/api.Event"
+            }
+        }
+    },
+    "api.UserCreated": {
+        "allOf": [
+            {
+                "$ref": "#/definitions/api.Event"
+            },
+            {
+                "type": "object",
+                "properties": {
+                    "kind": {
+                        "type": "string"
+                    },
+                    "name": {
+                        "type": "string"
+                    }
+                }
+            }
+        ],
+        "x-discriminator-value": "created"
+    },
+    "api.UserDeleted": {
+        "allOf": [
+            {
+                "$ref": "#/definitions/api.Event"
+            },
+            {
+                "type": "object",
+                "properties": {
+                    "id": {
+                        "type": "integer"
+                    },
+                    "kind": {
+                        "type": "string"
+                    }
+                }
+            }
+        ]
+    }
+}`
+	assert.Equal(t, expected, string(b))
+}
+
+func TestParsePolymorphism(t *testing.T) {
+	t.Parallel()
+
+	parse := func(doc string) (*polymorphism, error) {
+		p := New()
+		require.NoError(t, p.packages.ParseFile("api", "api/api.go", "package api\n\n"+doc+"type Event interface{}\n", ParseAll))
+
+		_, err := p.packages.ParseTypes()
+		require.NoError(t, err)
+
+		return parsePolymorphism(p.packages.FindTypeSpec("api.Event", nil))
+	}
+
+	poly, err := parse("// Event happened.\n")
+	require.NoError(t, err)
+	assert.Nil(t, poly)
+
+	poly, err = parse("// @discriminator type\n// @implementation a A\n// @implementation B\n")
+	require.NoError(t, err)
+	assert.Equal(t, &polymorphism{
+		discriminator:   "type",
+		implementations: []implementation{{value: "a", typeName: "A"}, {typeName: "B"}},
+	}, poly)
+
+	_, err = parse("// @discriminator type\n")
+	assert.EqualError(t, err, "@discriminator of api.Event needs an @implementation")
+
+	_, err = parse("// @implementation A\n")
+	assert.EqualError(t, err, "@implementation of api.Event needs a @discriminator")
+
+	_, err = parse("// @discriminator\n// @implementation A\n")
+	assert.EqualError(t, err, "@discriminator of api.Event needs a property name")
+}
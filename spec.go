@@ -1,14 +1,21 @@
-
 package swag
 
 import (
 	"bytes"
 	"encoding/json"
+	"fmt"
+	"net/url"
 	"strings"
+	"sync"
 	"text/template"
+
+	"github.com/go-openapi/spec"
 )
 
 // Spec holds exported Swagger Info so clients can modify it.
+//
+// ReadDoc caches the rendered document until one of these fields changes. Use the setters rather than
+// assigning the fields directly when the document may be read concurrently.
 type Spec struct {
 	Version          string
 	Host             string
@@ -18,40 +25,317 @@ type Spec struct {
 	Description      string
 	InfoInstanceName string
 	SwaggerTemplate  string
+
+	// SwaggerJSON is a rendered swagger document, e.g. an embedded swagger.json, used when SwaggerTemplate
+	// is empty. The fields above are patched into it.
+	SwaggerJSON string
+
+	mu sync.RWMutex
+	// info holds the extra info fields patched into the rendered document.
+	info map[string]interface{}
+	// tpl is SwaggerTemplate compiled, tplText the SwaggerTemplate it was compiled from.
+	tpl     *template.Template
+	tplText string
+	// doc is the document rendered from state, valid until the fields or info change.
+	doc   string
+	state specState
+	valid bool
+}
+
+// specState is a comparable snapshot of the fields rendered into the document.
+type specState struct {
+	version         string
+	host            string
+	basePath        string
+	schemes         string
+	title           string
+	description     string
+	swaggerTemplate string
+	swaggerJSON     string
+}
+
+var specFuncs = template.FuncMap{
+	"marshal": func(v interface{}) string {
+		a, _ := json.Marshal(v)
+
+		return string(a)
+	},
+	"escape": func(v interface{}) string {
+		// escape newlines and tabs
+		var str = strings.ReplaceAll(v.(string), "\n", "\\n")
+		str = strings.ReplaceAll(str, "\t", "\\t")
+		// replace " with \", and if that results in \\", replace that with \\\"
+		str = strings.ReplaceAll(str, "\"", "\\\"")
+
+		return strings.ReplaceAll(str, "\\\\\"", "\\\\\\\"")
+	},
 }
 
 // ReadDoc parses SwaggerTemplate into swagger document.
+// It is safe for concurrent use.
 func (i *Spec) ReadDoc() string {
-	i.Description = strings.ReplaceAll(i.Description, "\n", "\\n")
-
-	tpl, err := template.New("swagger_info").Funcs(template.FuncMap{
-		"marshal": func(v interface{}) string {
-			a, _ := json.Marshal(v)
-
-			return string(a)
-		},
-		"escape": func(v interface{}) string {
-			// escape tabs
-			var str = strings.ReplaceAll(v.(string), "\t", "\\t")
-			// replace " with \", and if that results in \\", replace that with \\\"
-			str = strings.ReplaceAll(str, "\"", "\\\"")
-
-			return strings.ReplaceAll(str, "\\\\\"", "\\\\\\\"")
-		},
-	}).Parse(i.SwaggerTemplate)
-	if err != nil {
-		return i.SwaggerTemplate
+	i.mu.RLock()
+	if i.valid && i.state == i.currentState() {
+		doc := i.doc
+		i.mu.RUnlock()
+
+		return doc
+	}
+	i.mu.RUnlock()
+
+	i.mu.Lock()
+	defer i.mu.Unlock()
+
+	state := i.currentState()
+	if i.valid && i.state == state {
+		return i.doc
+	}
+
+	i.doc, i.state, i.valid = i.render(), state, true
+
+	return i.doc
+}
+
+func (i *Spec) currentState() specState {
+	return specState{
+		version:         i.Version,
+		host:            i.Host,
+		basePath:        i.BasePath,
+		schemes:         strings.Join(i.Schemes, "\x00"),
+		title:           i.Title,
+		description:     i.Description,
+		swaggerTemplate: i.SwaggerTemplate,
+		swaggerJSON:     i.SwaggerJSON,
+	}
+}
+
+// render executes the compiled template. It must be called with the write lock held.
+func (i *Spec) render() string {
+	if i.SwaggerTemplate == "" && i.SwaggerJSON != "" {
+		doc := i.patchDoc()
+		if len(i.info) == 0 {
+			return doc
+		}
+
+		return patchInfo([]byte(doc), i.info)
+	}
+
+	if i.tpl == nil || i.tplText != i.SwaggerTemplate {
+		tpl, err := template.New("swagger_info").Funcs(specFuncs).Parse(i.SwaggerTemplate)
+		if err != nil {
+			return i.SwaggerTemplate
+		}
+
+		i.tpl, i.tplText = tpl, i.SwaggerTemplate
 	}
 
 	var doc bytes.Buffer
-	if err = tpl.Execute(&doc, i); err != nil {
+	if err := i.tpl.Execute(&doc, i); err != nil {
 		return i.SwaggerTemplate
 	}
 
-	return doc.String()
+	if len(i.info) == 0 {
+		return doc.String()
+	}
+
+	return patchInfo(doc.Bytes(), i.info)
+}
+
+// patchDoc sets the overridable fields in SwaggerJSON.
+func (i *Spec) patchDoc() string {
+	var swagger spec.Swagger
+
+	if err := json.Unmarshal([]byte(i.SwaggerJSON), &swagger); err != nil {
+		return i.SwaggerJSON
+	}
+
+	if swagger.Info == nil {
+		swagger.Info = &spec.Info{}
+	}
+
+	swagger.Host = i.Host
+	swagger.BasePath = i.BasePath
+	swagger.Schemes = i.Schemes
+	swagger.Info.Title = i.Title
+	swagger.Info.Description = i.Description
+	swagger.Info.Version = i.Version
+
+	doc, err := json.MarshalIndent(&swagger, "", "    ")
+	if err != nil {
+		return i.SwaggerJSON
+	}
+
+	return string(doc)
+}
+
+// patchInfo sets the given fields of the info object in a rendered document.
+func patchInfo(doc []byte, fields map[string]interface{}) string {
+	var swagger map[string]interface{}
+
+	decoder := json.NewDecoder(bytes.NewReader(doc))
+	decoder.UseNumber()
+
+	if err := decoder.Decode(&swagger); err != nil {
+		return string(doc)
+	}
+
+	info, _ := swagger["info"].(map[string]interface{})
+	if info == nil {
+		info = map[string]interface{}{}
+	}
+
+	for name, value := range fields {
+		info[name] = value
+	}
+
+	swagger["info"] = info
+
+	patched, err := json.MarshalIndent(swagger, "", "    ")
+	if err != nil {
+		return string(doc)
+	}
+
+	return string(patched)
 }
 
 // InstanceName returns Spec instance name.
 func (i *Spec) InstanceName() string {
 	return i.InfoInstanceName
-}
\ No newline at end of file
+}
+
+// SetHost sets the host serving the API.
+func (i *Spec) SetHost(host string) {
+	i.mu.Lock()
+	defer i.mu.Unlock()
+
+	i.Host = host
+}
+
+// SetBasePath sets the base path of the API.
+func (i *Spec) SetBasePath(basePath string) {
+	i.mu.Lock()
+	defer i.mu.Unlock()
+
+	i.BasePath = basePath
+}
+
+// SetSchemes sets the transfer protocols of the API.
+func (i *Spec) SetSchemes(schemes ...string) {
+	i.mu.Lock()
+	defer i.mu.Unlock()
+
+	i.Schemes = schemes
+}
+
+// SetServers sets the host, base path and schemes of the API from the URLs of its servers, e.g.
+// "https://api.example.com/v2". Swagger 2.0 has no servers, only a host and base path served with several
+// schemes, so the URLs may only differ in their scheme.
+func (i *Spec) SetServers(urls ...string) error {
+	var (
+		host, basePath string
+		schemes        []string
+	)
+
+	for n, rawURL := range urls {
+		server, err := url.Parse(rawURL)
+		if err != nil {
+			return err
+		}
+
+		if server.Scheme == "" || server.Host == "" {
+			return fmt.Errorf("server %s needs a scheme and a host", rawURL)
+		}
+
+		if n == 0 {
+			host, basePath = server.Host, server.Path
+		} else if server.Host != host || server.Path != basePath {
+			return fmt.Errorf("server %s differs from %s in more than its scheme", rawURL, urls[0])
+		}
+
+		if !findInSlice(schemes, server.Scheme) {
+			schemes = append(schemes, server.Scheme)
+		}
+	}
+
+	i.mu.Lock()
+	defer i.mu.Unlock()
+
+	i.Host = host
+	i.BasePath = basePath
+	i.Schemes = schemes
+
+	return nil
+}
+
+// SetTitle sets the title of the API.
+func (i *Spec) SetTitle(title string) {
+	i.mu.Lock()
+	defer i.mu.Unlock()
+
+	i.Title = title
+}
+
+// SetDescription sets the description of the API.
+func (i *Spec) SetDescription(description string) {
+	i.mu.Lock()
+	defer i.mu.Unlock()
+
+	i.Description = description
+}
+
+// SetVersion sets the version of the API.
+func (i *Spec) SetVersion(version string) {
+	i.mu.Lock()
+	defer i.mu.Unlock()
+
+	i.Version = version
+}
+
+// SetTermsOfService sets the terms of service of the API.
+func (i *Spec) SetTermsOfService(termsOfService string) {
+	i.setInfo("termsOfService", termsOfService)
+}
+
+// SetContact sets the contact information of the API.
+func (i *Spec) SetContact(name, url, email string) {
+	i.setInfo("contact", infoObject("name", name, "url", url, "email", email))
+}
+
+// SetLicense sets the license information of the API.
+func (i *Spec) SetLicense(name, url string) {
+	i.setInfo("license", infoObject("name", name, "url", url))
+}
+
+// SetInfoExtension sets a vendor extension of the info object. The name must start with "x-".
+func (i *Spec) SetInfoExtension(name string, value interface{}) {
+	if !strings.HasPrefix(strings.ToLower(name), "x-") {
+		return
+	}
+
+	i.setInfo(name, value)
+}
+
+func (i *Spec) setInfo(name string, value interface{}) {
+	i.mu.Lock()
+	defer i.mu.Unlock()
+
+	if i.info == nil {
+		i.info = make(map[string]interface{})
+	}
+
+	i.info[name] = value
+	i.valid = false
+}
+
+// infoObject builds an info sub-object from name/value pairs, leaving out empty values.
+func infoObject(pairs ...string) map[string]interface{} {
+	object := make(map[string]interface{})
+
+	for k := 0; k+1 < len(pairs); k += 2 {
+		if pairs[k+1] != "" {
+			object[pairs[k]] = pairs[k+1]
+		}
+	}
+
+	return object
+}
@@ -2,9 +2,12 @@
 package swag
 
 import (
+	"encoding/json"
+	"sync"
 	"testing"
 
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 )
 
 func TestSpec_InstanceName(t *testing.T) {
@@ -151,4 +154,191 @@ func TestSpec_ReadDoc(t *testing.T) {
 			assert.Equal(t, tt.want, doc.ReadDoc())
 		})
 	}
-}
\ No newline at end of file
+}
+
+const specTestTemplate = `{
+    "schemes": {{ marshal .Schemes }},
+    "swagger": "2.0",
+    "info": {
+        "description": "{{escape .Description}}",
+        "title": "{{.Title}}",
+        "version": "{{.Version}}"
+    },
+    "host": "{{.Host}}",
+    "basePath": "{{.BasePath}}"
+}`
+
+func TestSpec_ReadDocRepeated(t *testing.T) {
+	doc := &Spec{
+		Description:     "line one\nline \"two\"",
+		SwaggerTemplate: specTestTemplate,
+	}
+
+	first := doc.ReadDoc()
+	assert.Equal(t, first, doc.ReadDoc())
+	assert.Equal(t, "line one\nline \"two\"", doc.Description)
+
+	var swagger map[string]interface{}
+	require.NoError(t, json.Unmarshal([]byte(first), &swagger))
+	assert.Equal(t, "line one\nline \"two\"", swagger["info"].(map[string]interface{})["description"])
+}
+
+func TestSpec_ReadDocInvalidation(t *testing.T) {
+	doc := &Spec{
+		Host:            "localhost:8080",
+		Schemes:         []string{"http"},
+		SwaggerTemplate: specTestTemplate,
+	}
+
+	assert.Contains(t, doc.ReadDoc(), `"host": "localhost:8080"`)
+
+	doc.Host = "example.com"
+	assert.Contains(t, doc.ReadDoc(), `"host": "example.com"`)
+
+	doc.SetHost("api.example.com")
+	doc.SetBasePath("/v2")
+	doc.SetSchemes("https", "wss")
+	doc.SetTitle("Petstore")
+	doc.SetVersion("2.0.1")
+	doc.SetDescription("pets")
+
+	read := doc.ReadDoc()
+	assert.Contains(t, read, `"host": "api.example.com"`)
+	assert.Contains(t, read, `"basePath": "/v2"`)
+	assert.Contains(t, read, `"schemes": ["https","wss"]`)
+	assert.Contains(t, read, `"title": "Petstore"`)
+	assert.Contains(t, read, `"version": "2.0.1"`)
+	assert.Contains(t, read, `"description": "pets"`)
+}
+
+func TestSpec_SetServers(t *testing.T) {
+	doc := &Spec{
+		Host:            "localhost:8080",
+		Schemes:         []string{"http"},
+		SwaggerTemplate: specTestTemplate,
+	}
+
+	require.NoError(t, doc.SetServers("https://api.example.com/v2", "wss://api.example.com/v2", "https://api.example.com/v2"))
+
+	read := doc.ReadDoc()
+	assert.Contains(t, read, `"host": "api.example.com"`)
+	assert.Contains(t, read, `"basePath": "/v2"`)
+	assert.Contains(t, read, `"schemes": ["https","wss"]`)
+
+	assert.EqualError(t, doc.SetServers("https://api.example.com/v2", "https://api.example.com/v3"),
+		"server https://api.example.com/v3 differs from https://api.example.com/v2 in more than its scheme")
+	assert.EqualError(t, doc.SetServers("api.example.com"), "server api.example.com needs a scheme and a host")
+	assert.Error(t, doc.SetServers("https://api.example.com/%zz"))
+	assert.Equal(t, "api.example.com", doc.Host)
+}
+
+func TestSpec_SetInfoFields(t *testing.T) {
+	doc := &Spec{
+		Title:           "Petstore",
+		SwaggerTemplate: specTestTemplate,
+	}
+
+	doc.SetTermsOfService("http://swagger.io/terms/")
+	doc.SetContact("API Support", "", "support@swagger.io")
+	doc.SetLicense("Apache 2.0", "http://www.apache.org/licenses/LICENSE-2.0.html")
+	doc.SetInfoExtension("x-audience", "internal")
+	doc.SetInfoExtension("audience", "ignored")
+
+	var swagger map[string]interface{}
+	require.NoError(t, json.Unmarshal([]byte(doc.ReadDoc()), &swagger))
+	assert.Equal(t, map[string]interface{}{
+		"title":          "Petstore",
+		"description":    "",
+		"version":        "",
+		"termsOfService": "http://swagger.io/terms/",
+		"contact":        map[string]interface{}{"name": "API Support", "email": "support@swagger.io"},
+		"license": map[string]interface{}{
+			"name": "Apache 2.0",
+			"url":  "http://www.apache.org/licenses/LICENSE-2.0.html",
+		},
+		"x-audience": "internal",
+	}, swagger["info"])
+	assert.Equal(t, "2.0", swagger["swagger"])
+}
+
+func TestSpec_ReadDocConcurrent(t *testing.T) {
+	doc := &Spec{
+		Description:     "line one\nline two",
+		SwaggerTemplate: specTestTemplate,
+	}
+
+	want := doc.ReadDoc()
+
+	var wg sync.WaitGroup
+
+	for k := 0; k < 8; k++ {
+		wg.Add(2)
+
+		go func() {
+			defer wg.Done()
+
+			for n := 0; n < 50; n++ {
+				assert.Contains(t, doc.ReadDoc(), `"description": "line one\nline two"`)
+			}
+		}()
+
+		go func() {
+			defer wg.Done()
+
+			for n := 0; n < 50; n++ {
+				doc.SetHost("localhost:8080")
+				doc.SetTermsOfService("terms")
+			}
+		}()
+	}
+
+	wg.Wait()
+	assert.NotEqual(t, want, doc.ReadDoc())
+}
+
+func TestSpec_ReadDocSwaggerJSON(t *testing.T) {
+	rendered := `{
+    "swagger": "2.0",
+    "info": {
+        "description": "old",
+        "title": "Petstore",
+        "version": "1.0"
+    },
+    "host": "localhost:8080",
+    "basePath": "/v1",
+    "paths": {}
+}`
+
+	doc := &Spec{
+		Version:     "1.0",
+		Host:        "localhost:8080",
+		BasePath:    "/v1",
+		Schemes:     []string{},
+		Title:       "Petstore",
+		Description: "old",
+		SwaggerJSON: rendered,
+	}
+
+	assert.Equal(t, rendered, doc.ReadDoc())
+
+	doc.SetHost("api.example.com")
+	doc.SetSchemes("https")
+	doc.SetDescription("line one\nline two")
+	doc.SetLicense("MIT", "")
+
+	var swagger map[string]interface{}
+	require.NoError(t, json.Unmarshal([]byte(doc.ReadDoc()), &swagger))
+	assert.Equal(t, "api.example.com", swagger["host"])
+	assert.Equal(t, "/v1", swagger["basePath"])
+	assert.Equal(t, []interface{}{"https"}, swagger["schemes"])
+	assert.Equal(t, map[string]interface{}{
+		"description": "line one\nline two",
+		"title":       "Petstore",
+		"version":     "1.0",
+		"license":     map[string]interface{}{"name": "MIT"},
+	}, swagger["info"])
+
+	doc.SwaggerJSON = "{"
+	assert.Equal(t, "{", doc.ReadDoc())
+}
+
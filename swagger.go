@@ -1,9 +1,10 @@
-
 package swag
 
 import (
+	"encoding/json"
 	"errors"
 	"fmt"
+	"sort"
 	"sync"
 )
 
@@ -12,9 +13,23 @@ const Name = "swagger"
 
 var (
 	swaggerMu sync.RWMutex
-	swags     map[string]Swagger
+	swags     map[instanceKey]Swagger
 )
 
+// instanceKey identifies a registered instance. Version is empty unless registered with RegisterVersion.
+type instanceKey struct {
+	name    string
+	version string
+}
+
+func (k instanceKey) String() string {
+	if k.version == "" {
+		return k.name
+	}
+
+	return k.name + "@" + k.version
+}
+
 // Swagger is an interface to read swagger document.
 type Swagger interface {
 	ReadDoc() string
@@ -22,6 +37,27 @@ type Swagger interface {
 
 // Register registers swagger for given name.
 func Register(name string, swagger Swagger) {
+	register(instanceKey{name: name}, swagger, false)
+}
+
+// RegisterVersion registers swagger for given name and version, so that several versions of
+// one document can be served side by side.
+func RegisterVersion(name, version string, swagger Swagger) {
+	register(instanceKey{name: name, version: version}, swagger, false)
+}
+
+// Replace registers swagger for given name, replacing any instance already registered with that name.
+func Replace(name string, swagger Swagger) {
+	register(instanceKey{name: name}, swagger, true)
+}
+
+// ReplaceVersion registers swagger for given name and version, replacing any instance already registered
+// with that name and version.
+func ReplaceVersion(name, version string, swagger Swagger) {
+	register(instanceKey{name: name, version: version}, swagger, true)
+}
+
+func register(key instanceKey, swagger Swagger, replace bool) {
 	swaggerMu.Lock()
 	defer swaggerMu.Unlock()
 
@@ -30,28 +66,69 @@ func Register(name string, swagger Swagger) {
 	}
 
 	if swags == nil {
-		swags = make(map[string]Swagger)
+		swags = make(map[instanceKey]Swagger)
 	}
 
-	if _, ok := swags[name]; ok {
-		panic("Register called twice for swag: " + name)
+	if _, ok := swags[key]; ok && !replace {
+		panic("Register called twice for swag: " + key.String())
 	}
 
-	swags[name] = swagger
+	swags[key] = swagger
+}
+
+// Unregister removes the swagger instance registered for given name.
+// It returns false if there was none.
+func Unregister(name string) bool {
+	return unregister(instanceKey{name: name})
+}
+
+// UnregisterVersion removes the swagger instance registered for given name and version.
+// It returns false if there was none.
+func UnregisterVersion(name, version string) bool {
+	return unregister(instanceKey{name: name, version: version})
+}
+
+func unregister(key instanceKey) bool {
+	swaggerMu.Lock()
+	defer swaggerMu.Unlock()
+
+	if _, ok := swags[key]; !ok {
+		return false
+	}
+
+	delete(swags, key)
+
+	return true
 }
 
 // GetSwagger returns the swagger instance for given name.
 // If not found, returns nil.
 func GetSwagger(name string) Swagger {
+	return GetSwaggerVersion(name, "")
+}
+
+// GetSwaggerVersion returns the swagger instance for given name and version.
+// If not found, returns nil.
+func GetSwaggerVersion(name, version string) Swagger {
 	swaggerMu.RLock()
 	defer swaggerMu.RUnlock()
 
-	return swags[name]
+	return swags[instanceKey{name: name, version: version}]
 }
 
 // ReadDoc reads swagger document. An optional name parameter can be passed to read a specific document.
 // The default name is "swagger".
 func ReadDoc(optionalName ...string) (string, error) {
+	name := Name
+	if len(optionalName) != 0 && optionalName[0] != "" {
+		name = optionalName[0]
+	}
+
+	return ReadDocVersion(name, "")
+}
+
+// ReadDocVersion reads the swagger document registered for given name and version.
+func ReadDocVersion(name, version string) (string, error) {
 	swaggerMu.RLock()
 	defer swaggerMu.RUnlock()
 
@@ -59,15 +136,68 @@ func ReadDoc(optionalName ...string) (string, error) {
 		return "", errors.New("no swag has yet been registered")
 	}
 
-	name := Name
-	if len(optionalName) != 0 && optionalName[0] != "" {
-		name = optionalName[0]
-	}
+	key := instanceKey{name: name, version: version}
 
-	swag, ok := swags[name]
+	swag, ok := swags[key]
 	if !ok {
-		return "", fmt.Errorf("no swag named \"%s\" was registered", name)
+		return "", fmt.Errorf("no swag named \"%s\" was registered", key)
 	}
 
 	return swag.ReadDoc(), nil
-}
\ No newline at end of file
+}
+
+// InstanceInfo describes a registered swagger instance.
+type InstanceInfo struct {
+	Name string
+	// Version is the version given to RegisterVersion, empty for instances registered with Register.
+	Version string
+
+	// Title, DocVersion and BasePath are read from the document.
+	Title      string
+	DocVersion string
+	BasePath   string
+}
+
+// Instances lists the registered swagger instances sorted by name and version.
+func Instances() []InstanceInfo {
+	swaggerMu.RLock()
+	defer swaggerMu.RUnlock()
+
+	instances := make([]InstanceInfo, 0, len(swags))
+
+	for key, swagger := range swags {
+		instance := InstanceInfo{
+			Name:    key.name,
+			Version: key.version,
+		}
+
+		if spec, ok := swagger.(*Spec); ok {
+			spec.mu.RLock()
+			instance.Title, instance.DocVersion, instance.BasePath = spec.Title, spec.Version, spec.BasePath
+			spec.mu.RUnlock()
+		} else {
+			var doc struct {
+				Info struct {
+					Title   string `json:"title"`
+					Version string `json:"version"`
+				} `json:"info"`
+				BasePath string `json:"basePath"`
+			}
+
+			_ = json.Unmarshal([]byte(swagger.ReadDoc()), &doc)
+			instance.Title, instance.DocVersion, instance.BasePath = doc.Info.Title, doc.Info.Version, doc.BasePath
+		}
+
+		instances = append(instances, instance)
+	}
+
+	sort.Slice(instances, func(i, j int) bool {
+		if instances[i].Name != instances[j].Name {
+			return instances[i].Name < instances[j].Name
+		}
+
+		return instances[i].Version < instances[j].Version
+	})
+
+	return instances
+}
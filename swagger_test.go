@@ -230,4 +230,76 @@ func TestGetSwagger(t *testing.T) {
 
 	swagger = GetSwagger("invalid")
 	assert.Nil(t, swagger)
-}
\ No newline at end of file
+}
+
+func TestReplace(t *testing.T) {
+	setup()
+	Register(Name, &s{})
+	Replace(Name, staticDoc(handlerDoc))
+	d, _ := ReadDoc()
+	assert.Equal(t, handlerDoc, d)
+
+	Replace("another_name", &s{})
+	d, _ = ReadDoc("another_name")
+	assert.Equal(t, doc, d)
+}
+
+func TestUnregister(t *testing.T) {
+	setup()
+	Register(Name, &s{})
+	assert.True(t, Unregister(Name))
+	assert.False(t, Unregister(Name))
+	assert.Nil(t, GetSwagger(Name))
+
+	_, err := ReadDoc()
+	assert.Error(t, err)
+
+	assert.NotPanics(t, func() {
+		Register(Name, &s{})
+	})
+}
+
+func TestRegisterVersion(t *testing.T) {
+	setup()
+	Register(Name, &s{})
+	RegisterVersion(Name, "v1", staticDoc("v1"))
+	RegisterVersion(Name, "v2", staticDoc("v2"))
+
+	assert.Panics(t, func() {
+		RegisterVersion(Name, "v2", staticDoc("v2"))
+	})
+
+	d, _ := ReadDoc()
+	assert.Equal(t, doc, d)
+
+	d, _ = ReadDocVersion(Name, "v1")
+	assert.Equal(t, "v1", d)
+
+	ReplaceVersion(Name, "v2", staticDoc("v2.1"))
+	d, _ = ReadDocVersion(Name, "v2")
+	assert.Equal(t, "v2.1", d)
+	assert.Equal(t, staticDoc("v2.1"), GetSwaggerVersion(Name, "v2"))
+
+	_, err := ReadDocVersion(Name, "v3")
+	assert.EqualError(t, err, `no swag named "swagger@v3" was registered`)
+
+	assert.True(t, UnregisterVersion(Name, "v1"))
+	assert.NotNil(t, GetSwagger(Name))
+	assert.Nil(t, GetSwaggerVersion(Name, "v1"))
+}
+
+func TestInstances(t *testing.T) {
+	setup()
+	assert.Empty(t, Instances())
+
+	Register("petstore", staticDoc(handlerDoc))
+	RegisterVersion("petstore", "v2", &Spec{Title: "Petstore", Version: "2.0", BasePath: "/v2"})
+	Register("admin", &Spec{Title: "Admin", Version: "0.1", BasePath: "/admin"})
+
+	assert.Equal(t, []InstanceInfo{
+		{Name: "admin", Title: "Admin", DocVersion: "0.1", BasePath: "/admin"},
+		{Name: "petstore", Title: "Swagger Example API", DocVersion: "1.0", BasePath: "/v2"},
+		{Name: "petstore", Version: "v2", Title: "Petstore", DocVersion: "2.0", BasePath: "/v2"},
+	}, Instances())
+}
+
@@ -0,0 +1,231 @@
+// Package swagtest asserts that real HTTP responses conform to a registered swagger document.
+package swagtest
+
+import (
+	"encoding/json"
+	"fmt"
+	"mime"
+	"net/http"
+	"net/http/httptest"
+	"sort"
+	"strings"
+
+	"github.com/go-openapi/spec"
+
+	"github.com/swaggo/swag"
+	"github.com/swaggo/swag/internal/specvalidate"
+)
+
+// Spec validates HTTP exchanges against a swagger document.
+type Spec struct {
+	swagger *spec.Swagger
+	router  *specvalidate.Router
+}
+
+// Load reads the swagger document registered with swag.Register. An optional name parameter can be passed
+// to read a specific document instance.
+func Load(optionalName ...string) (*Spec, error) {
+	doc, err := swag.ReadDoc(optionalName...)
+	if err != nil {
+		return nil, err
+	}
+
+	return New(doc)
+}
+
+// New creates Spec from a swagger document in JSON format.
+func New(doc string) (*Spec, error) {
+	var swagger spec.Swagger
+
+	err := json.Unmarshal([]byte(doc), &swagger)
+	if err != nil {
+		return nil, fmt.Errorf("cannot parse swagger document: %w", err)
+	}
+
+	return NewFromSwagger(&swagger), nil
+}
+
+// NewFromSwagger creates Spec from an already parsed swagger document.
+func NewFromSwagger(swagger *spec.Swagger) *Spec {
+	return &Spec{
+		swagger: swagger,
+		router:  specvalidate.NewRouter(swagger),
+	}
+}
+
+// Swagger returns the underlying swagger document.
+func (s *Spec) Swagger() *spec.Swagger {
+	return s.swagger
+}
+
+// FindOperation returns the operation and its path template documented for the given method and request path.
+// The request path must include the basePath of the document.
+func (s *Spec) FindOperation(method, path string) (*spec.Operation, string, bool) {
+	route, ok := s.router.Find(method, path)
+	if !ok {
+		return nil, "", false
+	}
+
+	return route.Operation, route.Path, true
+}
+
+// ValidationError reports every mismatch between a response and the documented operation.
+type ValidationError struct {
+	Method   string
+	Path     string
+	Problems []string
+}
+
+// Error implements error.
+func (e *ValidationError) Error() string {
+	return fmt.Sprintf("%s %s: response does not match the spec:\n\t%s",
+		e.Method, e.Path, strings.Join(e.Problems, "\n\t"))
+}
+
+// ValidateResponse checks the status code, documented headers and JSON body recorded for req.
+func (s *Spec) ValidateResponse(req *http.Request, rec *httptest.ResponseRecorder) error {
+	op, path, ok := s.FindOperation(req.Method, req.URL.Path)
+	if !ok {
+		return fmt.Errorf("%s %s: no operation documented", req.Method, req.URL.Path)
+	}
+
+	verr := &ValidationError{Method: req.Method, Path: path}
+
+	response, ok := findResponse(s.swagger, op, rec.Code)
+	if !ok {
+		verr.Problems = append(verr.Problems, fmt.Sprintf("status %d is not documented", rec.Code))
+
+		return verr
+	}
+
+	verr.Problems = append(verr.Problems, validateHeaders(response.Headers, rec.Header())...)
+
+	if response.Schema != nil {
+		verr.Problems = append(verr.Problems, s.validateBody(op, response.Schema, rec)...)
+	}
+
+	if len(verr.Problems) > 0 {
+		return verr
+	}
+
+	return nil
+}
+
+// TestingT is the subset of testing.TB used by AssertResponse.
+type TestingT interface {
+	Helper()
+	Errorf(format string, args ...interface{})
+}
+
+// AssertResponse reports a test error when the recorded response does not conform to the spec.
+func (s *Spec) AssertResponse(t TestingT, req *http.Request, rec *httptest.ResponseRecorder) bool {
+	t.Helper()
+
+	err := s.ValidateResponse(req, rec)
+	if err != nil {
+		t.Errorf("%s", err)
+
+		return false
+	}
+
+	return true
+}
+
+func (s *Spec) validateBody(op *spec.Operation, schema *spec.Schema, rec *httptest.ResponseRecorder) []string {
+	contentType := rec.Header().Get("Content-Type")
+	mediaType, _, _ := mime.ParseMediaType(contentType)
+
+	produces := op.Produces
+	if len(produces) == 0 {
+		produces = s.swagger.Produces
+	}
+
+	if len(produces) > 0 && !containsMediaType(produces, mediaType) {
+		return []string{fmt.Sprintf("content type %q is not one of %s", contentType, strings.Join(produces, ", "))}
+	}
+
+	if mediaType != "" && !strings.HasSuffix(mediaType, "json") {
+		return nil
+	}
+
+	var value interface{}
+
+	err := json.Unmarshal(rec.Body.Bytes(), &value)
+	if err != nil {
+		return []string{fmt.Sprintf("body is not valid JSON: %s", err)}
+	}
+
+	v := specvalidate.SchemaValidator{Definitions: s.swagger.Definitions}
+	v.Validate(schema, value, "$")
+
+	return v.Problems
+}
+
+func findResponse(swagger *spec.Swagger, op *spec.Operation, code int) (*spec.Response, bool) {
+	if op.Responses == nil {
+		return nil, false
+	}
+
+	response, ok := op.Responses.StatusCodeResponses[code]
+	if ok {
+		return resolveResponse(swagger, &response)
+	}
+
+	if op.Responses.Default != nil {
+		return resolveResponse(swagger, op.Responses.Default)
+	}
+
+	return nil, false
+}
+
+// resolveResponse resolves a reference to a response declared in the responses of the spec.
+func resolveResponse(swagger *spec.Swagger, response *spec.Response) (*spec.Response, bool) {
+	ref := response.Ref.String()
+	if ref == "" {
+		return response, true
+	}
+
+	resolved, ok := swagger.Responses[strings.TrimPrefix(ref, "#/responses/")]
+	if !ok {
+		return nil, false
+	}
+
+	return &resolved, true
+}
+
+func validateHeaders(headers map[string]spec.Header, actual http.Header) []string {
+	names := make([]string, 0, len(headers))
+	for name := range headers {
+		names = append(names, name)
+	}
+
+	sort.Strings(names)
+
+	var problems []string
+
+	for _, name := range names {
+		value := actual.Get(name)
+		if value == "" {
+			problems = append(problems, fmt.Sprintf("header %s: missing", name))
+
+			continue
+		}
+
+		header := headers[name]
+		if !specvalidate.ValidPrimitive(header.Type, value) {
+			problems = append(problems, fmt.Sprintf("header %s: want %s, got %q", name, header.Type, value))
+		}
+	}
+
+	return problems
+}
+
+func containsMediaType(produces []string, mediaType string) bool {
+	for _, p := range produces {
+		if p == mediaType || p == "*/*" {
+			return true
+		}
+	}
+
+	return false
+}
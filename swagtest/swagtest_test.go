@@ -0,0 +1,223 @@
+package swagtest
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/swaggo/swag"
+)
+
+const petDoc = `{
+    "swagger": "2.0",
+    "basePath": "/api",
+    "produces": ["application/json"],
+    "paths": {
+        "/pets/{id}": {
+            "get": {
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {"$ref": "#/definitions/Pet"},
+                        "headers": {"X-Rate-Limit": {"type": "integer"}}
+                    },
+                    "404": {"description": "Not Found"}
+                }
+            }
+        },
+        "/pets/mine": {
+            "get": {
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {"type": "array", "items": {"$ref": "#/definitions/Pet"}}
+                    },
+                    "404": {"$ref": "#/responses/NotFound"}
+                }
+            }
+        }
+    },
+    "responses": {
+        "NotFound": {
+            "description": "Not Found",
+            "schema": {"$ref": "#/definitions/Error"}
+        }
+    },
+    "definitions": {
+        "Error": {
+            "type": "object",
+            "required": ["message"],
+            "properties": {"message": {"type": "string"}}
+        },
+        "Pet": {
+            "type": "object",
+            "required": ["name"],
+            "properties": {
+                "id": {"type": "integer"},
+                "name": {"type": "string", "maxLength": 8},
+                "status": {"type": "string", "enum": ["available", "sold"]},
+                "tags": {"type": "array", "items": {"type": "string"}}
+            }
+        }
+    }
+}`
+
+type fakeT struct {
+	errors []string
+}
+
+func (t *fakeT) Helper() {}
+
+func (t *fakeT) Errorf(format string, args ...interface{}) {
+	t.errors = append(t.errors, format)
+}
+
+func record(code int, contentType, body string, headers map[string]string) *httptest.ResponseRecorder {
+	rec := httptest.NewRecorder()
+	if contentType != "" {
+		rec.Header().Set("Content-Type", contentType)
+	}
+
+	for key, value := range headers {
+		rec.Header().Set(key, value)
+	}
+
+	rec.WriteHeader(code)
+	_, _ = rec.WriteString(body)
+
+	return rec
+}
+
+func TestSpec_FindOperation(t *testing.T) {
+	s, err := New(petDoc)
+	require.NoError(t, err)
+
+	_, path, ok := s.FindOperation(http.MethodGet, "/api/pets/1")
+	assert.True(t, ok)
+	assert.Equal(t, "/pets/{id}", path)
+
+	_, path, ok = s.FindOperation(http.MethodGet, "/api/pets/mine")
+	assert.True(t, ok)
+	assert.Equal(t, "/pets/mine", path)
+
+	_, _, ok = s.FindOperation(http.MethodPost, "/api/pets/1")
+	assert.False(t, ok)
+
+	_, _, ok = s.FindOperation(http.MethodGet, "/pets/1")
+	assert.False(t, ok)
+}
+
+func TestSpec_ValidateResponse(t *testing.T) {
+	s, err := New(petDoc)
+	require.NoError(t, err)
+
+	req := httptest.NewRequest(http.MethodGet, "/api/pets/1", nil)
+
+	t.Run("valid", func(t *testing.T) {
+		rec := record(http.StatusOK, "application/json; charset=utf-8",
+			`{"id":1,"name":"rex","status":"sold","tags":["a"]}`, map[string]string{"X-Rate-Limit": "10"})
+		assert.NoError(t, s.ValidateResponse(req, rec))
+	})
+
+	t.Run("body mismatch", func(t *testing.T) {
+		rec := record(http.StatusOK, "application/json",
+			`{"id":1.5,"name":1,"status":"lost","tags":["a",2]}`, map[string]string{"X-Rate-Limit": "ten"})
+
+		err := s.ValidateResponse(req, rec)
+		require.Error(t, err)
+
+		verr, ok := err.(*ValidationError)
+		require.True(t, ok)
+		assert.Equal(t, "/pets/{id}", verr.Path)
+		assert.Equal(t, []string{
+			`header X-Rate-Limit: want integer, got "ten"`,
+			"$.id: want integer, got 1.5",
+			"$.name: want string, got number",
+			"$.status: lost is not one of [available sold]",
+			"$.tags[1]: want string, got number",
+		}, verr.Problems)
+	})
+
+	t.Run("missing required", func(t *testing.T) {
+		rec := record(http.StatusOK, "application/json", `{"name":"too long name"}`, map[string]string{"X-Rate-Limit": "1"})
+
+		err := s.ValidateResponse(req, rec)
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "$.name: length 13 is greater than 8")
+
+		rec = record(http.StatusOK, "application/json", `{}`, map[string]string{"X-Rate-Limit": "1"})
+		err = s.ValidateResponse(req, rec)
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "$.name: required property is missing")
+	})
+
+	t.Run("undocumented status", func(t *testing.T) {
+		rec := record(http.StatusTeapot, "", "", nil)
+
+		err := s.ValidateResponse(req, rec)
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "status 418 is not documented")
+	})
+
+	t.Run("content type", func(t *testing.T) {
+		rec := record(http.StatusOK, "text/plain", "rex", map[string]string{"X-Rate-Limit": "1"})
+
+		err := s.ValidateResponse(req, rec)
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), `content type "text/plain" is not one of application/json`)
+	})
+
+	t.Run("array", func(t *testing.T) {
+		rec := record(http.StatusOK, "application/json", `[{"name":"rex"},{"id":2}]`, nil)
+
+		err := s.ValidateResponse(httptest.NewRequest(http.MethodGet, "/api/pets/mine", nil), rec)
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "$[1].name: required property is missing")
+	})
+
+	t.Run("referenced response", func(t *testing.T) {
+		req := httptest.NewRequest(http.MethodGet, "/api/pets/mine", nil)
+		assert.NoError(t, s.ValidateResponse(req, record(http.StatusNotFound, "application/json", `{"message":"no pets"}`, nil)))
+
+		err := s.ValidateResponse(req, record(http.StatusNotFound, "application/json", `{}`, nil))
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "$.message: required property is missing")
+	})
+
+	t.Run("no operation", func(t *testing.T) {
+		err := s.ValidateResponse(httptest.NewRequest(http.MethodGet, "/api/owners", nil), record(http.StatusOK, "", "", nil))
+		assert.EqualError(t, err, "GET /api/owners: no operation documented")
+	})
+}
+
+type stubDoc string
+
+func (s stubDoc) ReadDoc() string {
+	return string(s)
+}
+
+func TestLoad(t *testing.T) {
+	swag.Register("swagtest", stubDoc(petDoc))
+
+	s, err := Load("swagtest")
+	require.NoError(t, err)
+	assert.Equal(t, "/api", s.Swagger().BasePath)
+
+	ft := &fakeT{}
+	rec := record(http.StatusNotFound, "", "", nil)
+	assert.True(t, s.AssertResponse(ft, httptest.NewRequest(http.MethodGet, "/api/pets/1", nil), rec))
+	assert.Empty(t, ft.errors)
+
+	rec = record(http.StatusOK, "application/json", `{}`, nil)
+	assert.False(t, s.AssertResponse(ft, httptest.NewRequest(http.MethodGet, "/api/pets/1", nil), rec))
+	assert.Len(t, ft.errors, 1)
+
+	_, err = Load("unknown")
+	assert.Error(t, err)
+
+	_, err = New("{")
+	assert.Error(t, err)
+}
@@ -0,0 +1,445 @@
+// Package swagvalidate provides an http.Handler middleware that validates incoming requests against a
+// registered swagger document.
+package swagvalidate
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"log"
+	"mime"
+	"net/http"
+	"os"
+	"strings"
+
+	"github.com/go-openapi/spec"
+
+	"github.com/swaggo/swag"
+	"github.com/swaggo/swag/internal/specvalidate"
+)
+
+// defaultMaxMemory is the memory limit used to parse multipart forms, same as net/http.
+const defaultMaxMemory = 32 << 20
+
+// defaultMaxBodySize is the size limit of the bodies read for validation.
+const defaultMaxBodySize = 10 << 20
+
+// ErrBodyTooLarge is returned by ValidateRequest for a body larger than the size limit, the body is not validated.
+var ErrBodyTooLarge = errors.New("request body too large")
+
+// ErrorHandler writes the response for a request that does not match the spec.
+type ErrorHandler func(w http.ResponseWriter, r *http.Request, err *ValidationError)
+
+// Validator validates requests against the operations of a swagger document.
+type Validator struct {
+	instanceName string
+	swagger      *spec.Swagger
+	router       *specvalidate.Router
+	reportOnly   bool
+	errorHandler ErrorHandler
+	logger       swag.Debugger
+	maxBodySize  int64
+}
+
+// New creates Validator for the swagger document registered with swag.Register.
+func New(options ...func(*Validator)) (*Validator, error) {
+	validator := &Validator{
+		instanceName: swag.Name,
+		errorHandler: WriteProblem,
+		logger:       log.New(os.Stderr, "", log.LstdFlags),
+		maxBodySize:  defaultMaxBodySize,
+	}
+
+	for _, option := range options {
+		option(validator)
+	}
+
+	if validator.swagger == nil {
+		doc, err := swag.ReadDoc(validator.instanceName)
+		if err != nil {
+			return nil, err
+		}
+
+		var swagger spec.Swagger
+
+		err = json.Unmarshal([]byte(doc), &swagger)
+		if err != nil {
+			return nil, fmt.Errorf("cannot parse swagger document %s: %w", validator.instanceName, err)
+		}
+
+		validator.swagger = &swagger
+	}
+
+	validator.router = specvalidate.NewRouter(validator.swagger)
+
+	return validator, nil
+}
+
+// SetInstanceName sets the name of the registered swagger document, swag.Name by default.
+func SetInstanceName(name string) func(*Validator) {
+	return func(v *Validator) {
+		v.instanceName = name
+	}
+}
+
+// SetSwagger validates requests against swagger instead of a registered document.
+func SetSwagger(swagger *spec.Swagger) func(*Validator) {
+	return func(v *Validator) {
+		v.swagger = swagger
+	}
+}
+
+// SetReportOnly only logs invalid requests and passes them on to the next handler.
+func SetReportOnly(reportOnly bool) func(*Validator) {
+	return func(v *Validator) {
+		v.reportOnly = reportOnly
+	}
+}
+
+// SetErrorHandler replaces WriteProblem as the response written for invalid requests.
+func SetErrorHandler(handler ErrorHandler) func(*Validator) {
+	return func(v *Validator) {
+		v.errorHandler = handler
+	}
+}
+
+// SetLogger sets the logger used in report-only mode.
+func SetLogger(logger swag.Debugger) func(*Validator) {
+	return func(v *Validator) {
+		v.logger = logger
+	}
+}
+
+// SetMaxBodySize sets the size limit in bytes of the bodies read for validation, 10 MB by default.
+// Larger bodies are answered with 413 Request Entity Too Large.
+func SetMaxBodySize(size int64) func(*Validator) {
+	return func(v *Validator) {
+		v.maxBodySize = size
+	}
+}
+
+// Handler returns a middleware validating each request before calling next.
+// Requests that match no documented operation are passed on unchecked.
+func (v *Validator) Handler(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		err := v.ValidateRequest(r)
+		if errors.Is(err, ErrBodyTooLarge) {
+			if !v.reportOnly {
+				http.Error(w, err.Error(), http.StatusRequestEntityTooLarge)
+
+				return
+			}
+
+			v.logger.Printf("warning: %s %s: %s", r.Method, r.URL.Path, err)
+
+			err = nil
+		}
+
+		if err != nil {
+			verr, ok := err.(*ValidationError)
+			if !ok {
+				http.Error(w, err.Error(), http.StatusInternalServerError)
+
+				return
+			}
+
+			if !v.reportOnly {
+				v.errorHandler(w, r, verr)
+
+				return
+			}
+
+			v.logger.Printf("warning: %s", verr)
+		}
+
+		next.ServeHTTP(w, r)
+	})
+}
+
+// ValidationError reports every mismatch between a request and the documented operation.
+type ValidationError struct {
+	Method   string
+	Path     string
+	Problems []string
+}
+
+// Error implements error.
+func (e *ValidationError) Error() string {
+	return fmt.Sprintf("%s %s: request does not match the spec: %s",
+		e.Method, e.Path, strings.Join(e.Problems, "; "))
+}
+
+// ValidateRequest checks the params and body of r against its documented operation.
+// The body is restored, so it can still be read by the next handler. A body larger than the size limit
+// is not read fully, ErrBodyTooLarge is returned instead.
+func (v *Validator) ValidateRequest(r *http.Request) error {
+	route, ok := v.router.Find(r.Method, r.URL.Path)
+	if !ok {
+		return nil
+	}
+
+	verr := &ValidationError{Method: r.Method, Path: route.Path}
+
+	for _, param := range v.parameters(route) {
+		switch param.In {
+		case "path":
+			value, ok := route.Params[param.Name]
+			verr.Problems = append(verr.Problems, validateParam(&param, []string{value}, ok)...)
+		case "query":
+			values, ok := r.URL.Query()[param.Name]
+			verr.Problems = append(verr.Problems, validateParam(&param, values, ok)...)
+		case "header":
+			values := r.Header.Values(param.Name)
+			verr.Problems = append(verr.Problems, validateParam(&param, values, len(values) > 0)...)
+		case "formData":
+			problems, err := validateFormParam(&param, r)
+			if err != nil {
+				return err
+			}
+
+			verr.Problems = append(verr.Problems, problems...)
+		case "body":
+			problems, err := v.validateBody(&param, r)
+			if err != nil {
+				return err
+			}
+
+			verr.Problems = append(verr.Problems, problems...)
+		}
+	}
+
+	if len(verr.Problems) > 0 {
+		return verr
+	}
+
+	return nil
+}
+
+// parameters returns the params of the operation merged with those of its path item, with references resolved.
+func (v *Validator) parameters(route specvalidate.Route) []spec.Parameter {
+	var params []spec.Parameter
+
+	seen := map[string]bool{}
+
+	for _, list := range [][]spec.Parameter{route.Operation.Parameters, route.PathItem.Parameters} {
+		for _, param := range list {
+			ref := param.Ref.String()
+			if ref != "" {
+				resolved, ok := v.swagger.Parameters[strings.TrimPrefix(ref, "#/parameters/")]
+				if !ok {
+					continue
+				}
+
+				param = resolved
+			}
+
+			key := param.In + "." + param.Name
+			if seen[key] {
+				continue
+			}
+
+			seen[key] = true
+			params = append(params, param)
+		}
+	}
+
+	return params
+}
+
+func (v *Validator) validateBody(param *spec.Parameter, r *http.Request) ([]string, error) {
+	if r.Body == nil {
+		r.Body = http.NoBody
+	}
+
+	if r.ContentLength > v.maxBodySize {
+		return nil, ErrBodyTooLarge
+	}
+
+	body, err := io.ReadAll(io.LimitReader(r.Body, v.maxBodySize+1))
+	if err != nil {
+		return nil, fmt.Errorf("cannot read request body: %w", err)
+	}
+
+	if int64(len(body)) > v.maxBodySize {
+		// the rest of the body is left unread for the next handler
+		r.Body = readCloser{Reader: io.MultiReader(bytes.NewReader(body), r.Body), Closer: r.Body}
+
+		return nil, ErrBodyTooLarge
+	}
+
+	_ = r.Body.Close()
+	r.Body = io.NopCloser(bytes.NewReader(body))
+
+	if len(bytes.TrimSpace(body)) == 0 {
+		if param.Required {
+			return []string{"body: required"}, nil
+		}
+
+		return nil, nil
+	}
+
+	mediaType, _, _ := mime.ParseMediaType(r.Header.Get("Content-Type"))
+	if mediaType != "" && !strings.HasSuffix(mediaType, "json") {
+		return nil, nil
+	}
+
+	var value interface{}
+
+	err = json.Unmarshal(body, &value)
+	if err != nil {
+		return []string{fmt.Sprintf("body: invalid JSON: %s", err)}, nil
+	}
+
+	validator := specvalidate.SchemaValidator{Definitions: v.swagger.Definitions}
+	validator.Validate(param.Schema, value, "$")
+
+	return validator.Problems, nil
+}
+
+// readCloser reads from a Reader and closes a Closer, e.g. a partly read body.
+type readCloser struct {
+	io.Reader
+	io.Closer
+}
+
+func validateFormParam(param *spec.Parameter, r *http.Request) ([]string, error) {
+	mediaType, _, _ := mime.ParseMediaType(r.Header.Get("Content-Type"))
+
+	var err error
+	if mediaType == "multipart/form-data" {
+		if r.MultipartForm == nil {
+			err = r.ParseMultipartForm(defaultMaxMemory)
+		}
+	} else {
+		err = r.ParseForm()
+	}
+
+	if err != nil {
+		return []string{fmt.Sprintf("formData: %s", err)}, nil
+	}
+
+	if param.Type == "file" {
+		if param.Required && (r.MultipartForm == nil || len(r.MultipartForm.File[param.Name]) == 0) {
+			return []string{fmt.Sprintf("formData param %s: required", param.Name)}, nil
+		}
+
+		return nil, nil
+	}
+
+	values, ok := r.PostForm[param.Name]
+
+	return validateParam(param, values, ok), nil
+}
+
+func validateParam(param *spec.Parameter, values []string, present bool) []string {
+	prefix := fmt.Sprintf("%s param %s", param.In, param.Name)
+
+	if !present || (len(values) == 1 && values[0] == "" && !param.AllowEmptyValue) {
+		if param.Required {
+			return []string{prefix + ": required"}
+		}
+
+		return nil
+	}
+
+	if param.Type != "array" {
+		return validateValue(prefix, values[0], param.SimpleSchema, param.CommonValidations)
+	}
+
+	if param.CollectionFormat != "multi" {
+		values = splitCollection(values[0], param.CollectionFormat)
+	}
+
+	var problems []string
+
+	if param.MinItems != nil && int64(len(values)) < *param.MinItems {
+		problems = append(problems, fmt.Sprintf("%s: %d items is less than %d", prefix, len(values), *param.MinItems))
+	}
+
+	if param.MaxItems != nil && int64(len(values)) > *param.MaxItems {
+		problems = append(problems, fmt.Sprintf("%s: %d items is greater than %d", prefix, len(values), *param.MaxItems))
+	}
+
+	if param.Items == nil {
+		return problems
+	}
+
+	for i, value := range values {
+		problems = append(problems, validateValue(fmt.Sprintf("%s[%d]", prefix, i), value,
+			param.Items.SimpleSchema, param.Items.CommonValidations)...)
+	}
+
+	return problems
+}
+
+func splitCollection(value, collectionFormat string) []string {
+	switch collectionFormat {
+	case "ssv":
+		return strings.Split(value, " ")
+	case "tsv":
+		return strings.Split(value, "\t")
+	case "pipes":
+		return strings.Split(value, "|")
+	default:
+		return strings.Split(value, ",")
+	}
+}
+
+func validateValue(prefix, value string, schema spec.SimpleSchema, validations spec.CommonValidations) []string {
+	if !specvalidate.ValidPrimitive(schema.Type, value) {
+		return []string{fmt.Sprintf("%s: want %s, got %q", prefix, schema.Type, value)}
+	}
+
+	var problems []string
+
+	if len(validations.Enum) > 0 && !specvalidate.InEnum(validations.Enum, value) {
+		problems = append(problems, fmt.Sprintf("%s: %s is not one of %v", prefix, value, validations.Enum))
+	}
+
+	switch schema.Type {
+	case "integer", "number":
+		var number float64
+
+		_, _ = fmt.Sscan(value, &number)
+
+		if validations.Minimum != nil && (number < *validations.Minimum ||
+			validations.ExclusiveMinimum && number == *validations.Minimum) {
+			problems = append(problems, fmt.Sprintf("%s: %s is less than %v", prefix, value, *validations.Minimum))
+		}
+
+		if validations.Maximum != nil && (number > *validations.Maximum ||
+			validations.ExclusiveMaximum && number == *validations.Maximum) {
+			problems = append(problems, fmt.Sprintf("%s: %s is greater than %v", prefix, value, *validations.Maximum))
+		}
+	case "string", "":
+		problems = append(problems, specvalidate.ValidateString(prefix, value, validations.MinLength,
+			validations.MaxLength, validations.Pattern)...)
+	}
+
+	return problems
+}
+
+// Problem is the application/problem+json body written by WriteProblem, see RFC 7807.
+type Problem struct {
+	Type   string   `json:"type"`
+	Title  string   `json:"title"`
+	Status int      `json:"status"`
+	Detail string   `json:"detail"`
+	Errors []string `json:"errors"`
+}
+
+// WriteProblem is the default ErrorHandler. It writes a 400 application/problem+json response listing the problems.
+func WriteProblem(w http.ResponseWriter, _ *http.Request, err *ValidationError) {
+	w.Header().Set("Content-Type", "application/problem+json")
+	w.WriteHeader(http.StatusBadRequest)
+
+	_ = json.NewEncoder(w).Encode(Problem{
+		Type:   "about:blank",
+		Title:  http.StatusText(http.StatusBadRequest),
+		Status: http.StatusBadRequest,
+		Detail: fmt.Sprintf("%s %s does not match the spec", err.Method, err.Path),
+		Errors: err.Problems,
+	})
+}
@@ -0,0 +1,253 @@
+package swagvalidate
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/swaggo/swag"
+)
+
+const petDoc = `{
+    "swagger": "2.0",
+    "basePath": "/api",
+    "paths": {
+        "/pets/{id}": {
+            "put": {
+                "parameters": [
+                    {"type": "integer", "name": "id", "in": "path", "required": true, "minimum": 1},
+                    {"type": "string", "name": "X-Request-Id", "in": "header", "required": true, "pattern": "^[a-f0-9]+$"},
+                    {"type": "string", "name": "mode", "in": "query", "enum": ["fast", "safe"]},
+                    {"type": "array", "items": {"type": "integer", "maximum": 10}, "name": "ids", "in": "query", "maxItems": 3},
+                    {"name": "pet", "in": "body", "required": true, "schema": {"$ref": "#/definitions/Pet"}}
+                ],
+                "responses": {"200": {"description": "OK"}}
+            }
+        },
+        "/pets": {
+            "post": {
+                "parameters": [
+                    {"type": "string", "name": "name", "in": "formData", "required": true, "maxLength": 5}
+                ],
+                "responses": {"200": {"description": "OK"}}
+            }
+        }
+    },
+    "definitions": {
+        "Pet": {
+            "type": "object",
+            "required": ["name"],
+            "properties": {
+                "name": {"type": "string"}
+            }
+        }
+    }
+}`
+
+type stubDoc string
+
+func (s stubDoc) ReadDoc() string {
+	return string(s)
+}
+
+type recordLogger struct {
+	lines []string
+}
+
+func (l *recordLogger) Printf(format string, v ...interface{}) {
+	l.lines = append(l.lines, fmt.Sprintf(format, v...))
+}
+
+func init() {
+	swag.Register("swagvalidate", stubDoc(petDoc))
+}
+
+func echoHandler() http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_, _ = io.Copy(w, r.Body)
+	})
+}
+
+func newPutRequest(target, requestID, body string) *http.Request {
+	req := httptest.NewRequest(http.MethodPut, target, strings.NewReader(body))
+	req.Header.Set("Content-Type", "application/json")
+	if requestID != "" {
+		req.Header.Set("X-Request-Id", requestID)
+	}
+
+	return req
+}
+
+func TestValidator_ValidateRequest(t *testing.T) {
+	v, err := New(SetInstanceName("swagvalidate"))
+	require.NoError(t, err)
+
+	t.Run("valid", func(t *testing.T) {
+		req := newPutRequest("/api/pets/1?mode=fast&ids=1,2", "ab12", `{"name":"rex"}`)
+		assert.NoError(t, v.ValidateRequest(req))
+
+		body, err := io.ReadAll(req.Body)
+		require.NoError(t, err)
+		assert.Equal(t, `{"name":"rex"}`, string(body))
+	})
+
+	t.Run("invalid", func(t *testing.T) {
+		req := newPutRequest("/api/pets/0?mode=slow&ids=1,20,3,4", "XYZ", `{"name":1}`)
+
+		err := v.ValidateRequest(req)
+		require.Error(t, err)
+
+		verr, ok := err.(*ValidationError)
+		require.True(t, ok)
+		assert.Equal(t, "/pets/{id}", verr.Path)
+		assert.Equal(t, []string{
+			"path param id: 0 is less than 1",
+			`header param X-Request-Id: "XYZ" does not match pattern ^[a-f0-9]+$`,
+			"query param mode: slow is not one of [fast safe]",
+			"query param ids: 4 items is greater than 3",
+			"query param ids[1]: 20 is greater than 10",
+			"$.name: want string, got number",
+		}, verr.Problems)
+	})
+
+	t.Run("missing", func(t *testing.T) {
+		req := newPutRequest("/api/pets/abc", "", "")
+
+		err := v.ValidateRequest(req)
+		require.Error(t, err)
+		assert.Equal(t, []string{
+			`path param id: want integer, got "abc"`,
+			"header param X-Request-Id: required",
+			"body: required",
+		}, err.(*ValidationError).Problems)
+	})
+
+	t.Run("form", func(t *testing.T) {
+		req := httptest.NewRequest(http.MethodPost, "/api/pets", strings.NewReader("name=toolong"))
+		req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+
+		err := v.ValidateRequest(req)
+		require.Error(t, err)
+		assert.Equal(t, []string{"formData param name: length 7 is greater than 5"}, err.(*ValidationError).Problems)
+	})
+
+	t.Run("undocumented", func(t *testing.T) {
+		assert.NoError(t, v.ValidateRequest(httptest.NewRequest(http.MethodGet, "/api/owners", nil)))
+	})
+}
+
+func TestValidator_Handler(t *testing.T) {
+	t.Run("problem response", func(t *testing.T) {
+		v, err := New(SetInstanceName("swagvalidate"))
+		require.NoError(t, err)
+
+		rec := httptest.NewRecorder()
+		v.Handler(echoHandler()).ServeHTTP(rec, newPutRequest("/api/pets/1", "", `{}`))
+
+		assert.Equal(t, http.StatusBadRequest, rec.Code)
+		assert.Equal(t, "application/problem+json", rec.Header().Get("Content-Type"))
+
+		var problem Problem
+		require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &problem))
+		assert.Equal(t, Problem{
+			Type:   "about:blank",
+			Title:  "Bad Request",
+			Status: http.StatusBadRequest,
+			Detail: "PUT /pets/{id} does not match the spec",
+			Errors: []string{"header param X-Request-Id: required", "$.name: required property is missing"},
+		}, problem)
+
+		rec = httptest.NewRecorder()
+		v.Handler(echoHandler()).ServeHTTP(rec, newPutRequest("/api/pets/1", "ab", `{"name":"rex"}`))
+		assert.Equal(t, http.StatusOK, rec.Code)
+		assert.Equal(t, `{"name":"rex"}`, rec.Body.String())
+	})
+
+	t.Run("custom error handler", func(t *testing.T) {
+		v, err := New(SetInstanceName("swagvalidate"), SetErrorHandler(func(w http.ResponseWriter, _ *http.Request, err *ValidationError) {
+			http.Error(w, err.Error(), http.StatusUnprocessableEntity)
+		}))
+		require.NoError(t, err)
+
+		rec := httptest.NewRecorder()
+		v.Handler(echoHandler()).ServeHTTP(rec, newPutRequest("/api/pets/1", "ab", `{}`))
+		assert.Equal(t, http.StatusUnprocessableEntity, rec.Code)
+		assert.Contains(t, rec.Body.String(), "PUT /pets/{id}: request does not match the spec: $.name: required property is missing")
+	})
+
+	t.Run("report only", func(t *testing.T) {
+		logger := &recordLogger{}
+
+		v, err := New(SetInstanceName("swagvalidate"), SetReportOnly(true), SetLogger(logger))
+		require.NoError(t, err)
+
+		rec := httptest.NewRecorder()
+		v.Handler(echoHandler()).ServeHTTP(rec, newPutRequest("/api/pets/1", "ab", `{}`))
+		assert.Equal(t, http.StatusOK, rec.Code)
+		assert.Equal(t, `{}`, rec.Body.String())
+		assert.Equal(t, []string{
+			"warning: PUT /pets/{id}: request does not match the spec: $.name: required property is missing",
+		}, logger.lines)
+	})
+}
+
+func TestValidator_MaxBodySize(t *testing.T) {
+	const body = `{"name":"rex the dog"}`
+
+	v, err := New(SetInstanceName("swagvalidate"), SetMaxBodySize(8))
+	require.NoError(t, err)
+
+	t.Run("content length", func(t *testing.T) {
+		req := newPutRequest("/api/pets/1", "ab", body)
+		assert.ErrorIs(t, v.ValidateRequest(req), ErrBodyTooLarge)
+	})
+
+	t.Run("streamed", func(t *testing.T) {
+		req := newPutRequest("/api/pets/1", "ab", body)
+		req.ContentLength = -1
+		assert.ErrorIs(t, v.ValidateRequest(req), ErrBodyTooLarge)
+
+		read, err := io.ReadAll(req.Body)
+		require.NoError(t, err)
+		assert.Equal(t, body, string(read))
+	})
+
+	t.Run("handler", func(t *testing.T) {
+		rec := httptest.NewRecorder()
+		v.Handler(echoHandler()).ServeHTTP(rec, newPutRequest("/api/pets/1", "ab", body))
+		assert.Equal(t, http.StatusRequestEntityTooLarge, rec.Code)
+	})
+
+	t.Run("report only", func(t *testing.T) {
+		logger := &recordLogger{}
+
+		v, err := New(SetInstanceName("swagvalidate"), SetMaxBodySize(8), SetReportOnly(true), SetLogger(logger))
+		require.NoError(t, err)
+
+		req := newPutRequest("/api/pets/1", "ab", body)
+		req.ContentLength = -1
+
+		rec := httptest.NewRecorder()
+		v.Handler(echoHandler()).ServeHTTP(rec, req)
+		assert.Equal(t, http.StatusOK, rec.Code)
+		assert.Equal(t, body, rec.Body.String())
+		assert.Equal(t, []string{"warning: PUT /api/pets/1: request body too large"}, logger.lines)
+	})
+}
+
+func TestNew(t *testing.T) {
+	_, err := New(SetInstanceName("unknown"))
+	assert.Error(t, err)
+
+	swag.Register("swagvalidate-broken", stubDoc("{"))
+
+	_, err = New(SetInstanceName("swagvalidate-broken"))
+	assert.Error(t, err)
+}
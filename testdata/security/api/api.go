@@ -0,0 +1,32 @@
+package api
+
+// ListPets godoc
+// @Summary List pets
+// @Router /pets [get]
+func ListPets() {
+
+}
+
+// CreatePet godoc
+// @Summary Create a pet
+// @Security ApiKeyAuth && OAuth2Implicit[write]
+// @Router /pets [post]
+func CreatePet() {
+
+}
+
+// DeletePet godoc
+// @Summary Delete a pet
+// @Security OAuth2Implicit[read, write] || ApiKeyAuth && BasicAuth
+// @Router /pets [delete]
+func DeletePet() {
+
+}
+
+// Health godoc
+// @Summary Health check
+// @Security none
+// @Router /health [get]
+func Health() {
+
+}
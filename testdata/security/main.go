@@ -0,0 +1,21 @@
+package main
+
+// @title Swag test
+// @version 1.0
+// @description test for security requirements
+
+// @securityDefinitions.basic BasicAuth
+
+// @securityDefinitions.apikey ApiKeyAuth
+// @in header
+// @name Authorization
+
+// @securitydefinitions.oauth2.implicit OAuth2Implicit
+// @authorizationurl https://example.com/oauth/authorize
+// @scope.read Grants read access
+// @scope.write Grants write access
+
+// @Security ApiKeyAuth || BasicAuth
+func main() {
+
+}
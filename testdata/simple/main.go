@@ -28,6 +28,10 @@ import (
 // @in header
 // @name Authorization
 
+// @securityDefinitions.apikey Firebase
+// @in header
+// @name X-Firebase-Auth
+
 // @securitydefinitions.oauth2.application OAuth2Application
 // @tokenUrl https://example.com/oauth/token
 // @scope.write Grants write access
@@ -96,3 +96,34 @@ type AstFileInfo struct {
 	// ParseFlag determine what to parse
 	ParseFlag ParseFlag
 }
+
+// ParsedOperation an operation parsed from the doc comment of a Go function, with its source location.
+type ParsedOperation struct {
+	// Method the HTTP method of the route, e.g. GET
+	Method string
+
+	// Path the path of the route, as written in @Router
+	Path string
+
+	// Operation the operation added to the spec for the route
+	Operation *spec.Operation
+
+	// FuncName name of the handler function or method
+	FuncName string
+
+	// Receiver type of the method receiver as written in source, e.g. *Controller, empty for functions
+	Receiver string
+
+	// PackagePath package import path of the handler
+	PackagePath string
+
+	// File the path of the file declaring the handler
+	File string
+
+	// StartLine and EndLine the line range of the handler declaration, its doc comment excluded
+	StartLine int
+	EndLine   int
+
+	// Comments the raw doc comment lines of the handler, annotations included
+	Comments []string
+}
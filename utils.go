@@ -53,4 +53,40 @@ func FieldsFunc(s string, f func(rune2 rune) bool, n int) []string {
 // FieldsByAnySpace split a string s by any space character into max n parts
 func FieldsByAnySpace(s string, n int) []string {
 	return FieldsFunc(s, unicode.IsSpace, n)
-}
\ No newline at end of file
+}
+
+// editDistance returns the Levenshtein distance between a and b.
+func editDistance(a, b string) int {
+	s, t := []rune(a), []rune(b)
+
+	previous := make([]int, len(t)+1)
+	current := make([]int, len(t)+1)
+
+	for j := range previous {
+		previous[j] = j
+	}
+
+	for i := 1; i <= len(s); i++ {
+		current[0] = i
+
+		for j := 1; j <= len(t); j++ {
+			cost := 1
+			if s[i-1] == t[j-1] {
+				cost = 0
+			}
+
+			current[j] = previous[j-1] + cost
+			if previous[j]+1 < current[j] {
+				current[j] = previous[j] + 1
+			}
+
+			if current[j-1]+1 < current[j] {
+				current[j] = current[j-1] + 1
+			}
+		}
+
+		previous, current = current, previous
+	}
+
+	return previous[len(t)]
+}
@@ -36,3 +36,11 @@ func TestFieldsByAnySpace(t *testing.T) {
 		})
 	}
 }
+
+func TestEditDistance(t *testing.T) {
+	assert.Equal(t, 0, editDistance("@param", "@param"))
+	assert.Equal(t, 1, editDistance("@parm", "@param"))
+	assert.Equal(t, 2, editDistance("@sucess", "@successs"))
+	assert.Equal(t, 6, editDistance("", "@param"))
+	assert.Equal(t, 1, editDistance("é", "e"))
+}
@@ -0,0 +1,164 @@
+package swag
+
+import (
+	"encoding/json"
+	"go/ast"
+	"regexp"
+	"strings"
+
+	"github.com/go-openapi/spec"
+)
+
+// wellKnownSchema the schema of a well-known type.
+func wellKnownSchema(schemaType, format string, nullable bool) spec.Schema {
+	schema := *PrimitiveSchema(schemaType)
+	schema.Format = format
+
+	if nullable {
+		schema.AddExtension("x-nullable", true)
+	}
+
+	return schema
+}
+
+// defaultWellKnownTypes the schemas of common types of the standard library and popular modules, which their
+// declarations don't describe. They are keyed by import path and type name.
+func defaultWellKnownTypes() map[string]spec.Schema {
+	return map[string]spec.Schema{
+		"time.Duration": wellKnownSchema(INTEGER, "int64", false),
+
+		"encoding/json.RawMessage": {},
+
+		"database/sql.NullString":  wellKnownSchema(STRING, "", true),
+		"database/sql.NullBool":    wellKnownSchema(BOOLEAN, "", true),
+		"database/sql.NullByte":    wellKnownSchema(INTEGER, "", true),
+		"database/sql.NullInt16":   wellKnownSchema(INTEGER, "", true),
+		"database/sql.NullInt32":   wellKnownSchema(INTEGER, "int32", true),
+		"database/sql.NullInt64":   wellKnownSchema(INTEGER, "int64", true),
+		"database/sql.NullFloat64": wellKnownSchema(NUMBER, "double", true),
+		"database/sql.NullTime":    wellKnownSchema(STRING, "date-time", true),
+
+		"net.IP":             wellKnownSchema(STRING, "", false),
+		"net/netip.Addr":     wellKnownSchema(STRING, "", false),
+		"net/netip.AddrPort": wellKnownSchema(STRING, "", false),
+		"net/netip.Prefix":   wellKnownSchema(STRING, "", false),
+		"net/url.URL":        wellKnownSchema(STRING, "uri", false),
+
+		"github.com/google/uuid.UUID":       wellKnownSchema(STRING, "uuid", false),
+		"github.com/google/uuid.NullUUID":   wellKnownSchema(STRING, "uuid", true),
+		"github.com/gofrs/uuid.UUID":        wellKnownSchema(STRING, "uuid", false),
+		"github.com/gofrs/uuid.NullUUID":    wellKnownSchema(STRING, "uuid", true),
+		"github.com/gofrs/uuid/v5.UUID":     wellKnownSchema(STRING, "uuid", false),
+		"github.com/gofrs/uuid/v5.NullUUID": wellKnownSchema(STRING, "uuid", true),
+
+		"github.com/shopspring/decimal.Decimal":     wellKnownSchema(STRING, "decimal", false),
+		"github.com/shopspring/decimal.NullDecimal": wellKnownSchema(STRING, "decimal", true),
+	}
+}
+
+// SetWellKnownTypes registers the schemas of types which their declarations don't describe, keyed by import path
+// and type name, e.g. "github.com/google/uuid.UUID". They are added to the built-in ones, or replace them.
+// Overrides take precedence.
+func SetWellKnownTypes(types map[string]spec.Schema) func(parser *Parser) {
+	return func(p *Parser) {
+		for name, schema := range types {
+			p.wellKnownTypes[name] = schema
+		}
+	}
+}
+
+// getWellKnownTypeSchema returns a copy of the schema of a well-known type, the name of the type is qualified with
+// the name of its package as in the source of file, e.g. uuid.UUID. Overridden types aren't well-known,
+// their overrides take precedence.
+func (parser *Parser) getWellKnownTypeSchema(typeName string, file *ast.File) (*spec.Schema, bool) {
+	if len(parser.wellKnownTypes) == 0 {
+		return nil, false
+	}
+
+	fullNames := parser.packages.fullTypeNames(typeName, file)
+
+	for _, fullName := range fullNames {
+		if _, ok := parser.Overrides[fullName]; ok {
+			return nil, false
+		}
+	}
+
+	for _, fullName := range fullNames {
+		schema, ok := parser.wellKnownTypes[fullName]
+		if !ok {
+			continue
+		}
+
+		// a deep copy, the tags of a field are set on it, e.g. on the items of an array type
+		data, err := json.Marshal(schema)
+		if err != nil {
+			return nil, false
+		}
+
+		var result spec.Schema
+
+		err = json.Unmarshal(data, &result)
+		if err != nil {
+			return nil, false
+		}
+
+		return &result, true
+	}
+
+	return nil, false
+}
+
+var majorVersionPattern = regexp.MustCompile(`^v[0-9]+$`)
+
+// fullTypeNames returns the possible import paths and names of a type, qualified with the name of its package
+// as in the source of file, e.g. github.com/google/uuid.UUID for uuid.UUID.
+func (pkgDefs *PackagesDefinitions) fullTypeNames(typeName string, file *ast.File) []string {
+	names := []string{typeName}
+
+	if file == nil {
+		return names
+	}
+
+	separator := strings.LastIndex(typeName, ".")
+	if separator == -1 {
+		if info, ok := pkgDefs.files[file]; ok {
+			names = append(names, fullTypeName(info.PackagePath, typeName))
+		}
+
+		return names
+	}
+
+	pkg, name := typeName[:separator], typeName[separator+1:]
+
+	for _, imp := range file.Imports {
+		path := strings.Trim(imp.Path.Value, `"`)
+
+		if imp.Name != nil {
+			if imp.Name.Name == pkg {
+				names = append(names, fullTypeName(path, name))
+			}
+
+			continue
+		}
+
+		if pd, ok := pkgDefs.packages[path]; ok {
+			if pd.Name == pkg {
+				names = append(names, fullTypeName(path, name))
+			}
+
+			continue
+		}
+
+		// the package name is the last element of the import path, without the major version suffix
+		parts := strings.Split(path, "/")
+		if len(parts) > 1 && majorVersionPattern.MatchString(parts[len(parts)-1]) {
+			parts = parts[:len(parts)-1]
+		}
+
+		if parts[len(parts)-1] == pkg {
+			names = append(names, fullTypeName(path, name))
+		}
+	}
+
+	return names
+}
@@ -0,0 +1,191 @@
+package swag
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/go-openapi/spec"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParser_WellKnownTypes(t *testing.T) {
+	t.Parallel()
+
+	src := `
+package api
+
+import (
+	"database/sql"
+	stdjson "encoding/json"
+	"net/netip"
+	"net/url"
+	"time"
+
+	"github.com/gofrs/uuid/v5"
+	"github.com/shopspring/decimal"
+)
+
+type Cents int64
+
+type Item struct {
+	Timeout time.Duration      ` + "`json:\"timeout\"`" + `
+	ID      uuid.UUID          ` + "`json:\"id\"`" + `
+	Price   decimal.Decimal    ` + "`json:\"price\"`" + `
+	Note    sql.NullString     ` + "`json:\"note\"`" + `
+	Raw     stdjson.RawMessage ` + "`json:\"raw\"`" + `
+	Addr    netip.Addr         ` + "`json:\"addr\"`" + `
+	Link    *url.URL           ` + "`json:\"link\"`" + `
+	Total   Cents              ` + "`json:\"total\"`" + `
+}
+
+// @Success 200 {object} Item
+// @Router /items [get]
+func Get(){
+}
+`
+	cents := *PrimitiveSchema(STRING)
+	cents.Format = "cents"
+	cents.Example = "1234"
+
+	p := New(SetWellKnownTypes(map[string]spec.Schema{"example.com/api.Cents": cents}))
+	require.NoError(t, p.packages.ParseFile("example.com/api", "api/api.go", src, ParseAll))
+
+	_, err := p.packages.ParseTypes()
+	require.NoError(t, err)
+
+	require.NoError(t, p.packages.RangeFiles(p.ParseRouterAPIInfo))
+
+	b, _ := json.MarshalIndent(p.swagger.Definitions["api.Item"], "", "    ")
+	expected := `{
+    "type": "object",
+    "properties": {
+        "addr": {
+            "type": "string"
+        },
+        "id": {
+            "type": "string",
+            "format": "uuid"
+        },
+        "link": {
+            "type": "string",
+            "format": "uri"
+        },
+        "note": {
+            "type": "string",
+            "x-nullable": true
+        },
+        "price": {
+            "type": "string",
+            "format": "decimal"
+        },
+        "raw": {},
+        "timeout": {
+            "type": "integer",
+            "format": "int64"
+        },
+        "total": {
+            "type": "string",
+            "format": "cents",
+            "example": "1234"
+        }
+    }
+}`
+	assert.Equal(t, expected, string(b))
+}
+
+func TestParser_WellKnownTypesOverridden(t *testing.T) {
+	t.Parallel()
+
+	src := `
+package api
+
+type Cents int64
+
+type Label struct {
+	Text string
+}
+
+type Item struct {
+	Total Cents ` + "`json:\"total\"`" + `
+	Label Label ` + "`json:\"label\"`" + `
+	Count int   ` + "`json:\"count\"`" + `
+}
+
+// @Success 200 {object} Item
+// @Router /items [get]
+func Get(){
+}
+`
+	p := New(
+		SetWellKnownTypes(map[string]spec.Schema{
+			"example.com/api.Cents": *PrimitiveSchema(STRING),
+			"example.com/api.Label": *PrimitiveSchema(STRING),
+		}),
+		// as read from a .swaggo file with `replace example.com/api.Cents integer` and `skip example.com/api.Label`
+		SetOverrides(map[string]string{
+			"example.com/api.Cents": "integer",
+			"example.com/api.Label": "",
+		}),
+	)
+	require.NoError(t, p.packages.ParseFile("example.com/api", "api/api.go", src, ParseAll))
+
+	_, err := p.packages.ParseTypes()
+	require.NoError(t, err)
+
+	require.NoError(t, p.packages.RangeFiles(p.ParseRouterAPIInfo))
+
+	b, _ := json.MarshalIndent(p.swagger.Definitions["api.Item"], "", "    ")
+	expected := `{
+    "type": "object",
+    "properties": {
+        "count": {
+            "type": "integer"
+        },
+        "total": {
+            "type": "integer"
+        }
+    }
+}`
+	assert.Equal(t, expected, string(b))
+}
+
+func TestParser_WellKnownTypesCopied(t *testing.T) {
+	t.Parallel()
+
+	src := `
+package api
+
+type Emails []string
+
+type Item struct {
+	To Emails ` + "`json:\"to\" format:\"email\" enums:\"a@b.c,d@e.f\" maximum:\"3\"`" + `
+	Cc Emails ` + "`json:\"cc\"`" + `
+}
+
+// @Success 200 {object} Item
+// @Router /items [get]
+func Get(){
+}
+`
+	p := New(SetWellKnownTypes(map[string]spec.Schema{
+		"example.com/api.Emails": *spec.ArrayProperty(PrimitiveSchema(STRING)),
+	}))
+	require.NoError(t, p.packages.ParseFile("example.com/api", "api/api.go", src, ParseAll))
+
+	_, err := p.packages.ParseTypes()
+	require.NoError(t, err)
+
+	require.NoError(t, p.packages.RangeFiles(p.ParseRouterAPIInfo))
+
+	b, _ := json.MarshalIndent(p.swagger.Definitions["api.Item"].Properties["cc"], "", "    ")
+	expected := `{
+    "type": "array",
+    "items": {
+        "type": "string"
+    }
+}`
+	assert.Equal(t, expected, string(b))
+	assert.Equal(t, *spec.ArrayProperty(PrimitiveSchema(STRING)), p.wellKnownTypes["example.com/api.Emails"])
+	assert.Equal(t, "email", p.swagger.Definitions["api.Item"].Properties["to"].Items.Schema.Format)
+}